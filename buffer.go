@@ -0,0 +1,242 @@
+package keel
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/trippwill/keel/core"
+	"github.com/trippwill/keel/engine"
+	"github.com/trippwill/keel/logging"
+)
+
+// RenderBuffer arranges the stored spec at the given size and paints every
+// frame into a cell buffer at its allocated rect.
+//
+// The buffer is size.Width x size.Height cells unless a frame renders past
+// its allocation (for example with [Overflow]); such output is drawn beneath
+// later frames and grows the buffer where it extends past the layout. Use
+// RenderBuffer to feed custom backends; [Renderer.Render] serializes the same
+// buffer to a string.
+func (r *Renderer[KID]) RenderBuffer(size Size) (*cellbuf.Buffer, error) {
+	if r == nil {
+		return nil, ErrRendererMissing
+	}
+	if r.spec == nil {
+		return nil, ErrSpecMissing
+	}
+	ctx := context.Background()
+	layout, err := r.ensureLayout(ctx, size)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	buf, _, err := r.paintLayout(ctx, layout)
+	return buf, err
+}
+
+// paintLayout paints an arranged layout into a new buffer and reports whether
+// any frame was painted. With [ErrorModePlaceholder], failed frames are
+// painted as placeholders and the buffer is returned with their joined
+// errors; otherwise any error returns a nil buffer.
+func (r *Renderer[KID]) paintLayout(ctx context.Context, layout engine.Layout[KID]) (*cellbuf.Buffer, bool, error) {
+	buf := cellbuf.NewBuffer(layout.Width, layout.Height)
+	var failures *[]error
+	if r.config.ErrorMode() == ErrorModePlaceholder {
+		failures = &[]error{}
+	}
+	var painted bool
+	var err error
+	if workers := r.config.Concurrency(); workers > 1 {
+		painted, err = paintLayoutConcurrent(ctx, layout.Root, r, buf, workers, failures)
+	} else {
+		painted, err = paintLayoutWithPath(ctx, layout.Root, r, buf, layout.Root.Path, failures)
+	}
+	if err != nil {
+		return nil, false, convertError(err)
+	}
+	if failures != nil {
+		return buf, painted, errors.Join(*failures...)
+	}
+	return buf, painted, nil
+}
+
+func paintLayoutWithPath[KID KeelID](ctx context.Context, node engine.LayoutNode[KID], r *Renderer[KID], buf *cellbuf.Buffer, path string, failures *[]error) (bool, error) {
+	logger := rendererLogger(r)
+	switch node.Kind {
+	case engine.NodeStack:
+		if len(node.Slots) == 0 {
+			return false, nil
+		}
+		axis := node.Axis
+		if axis != core.AxisHorizontal && axis != core.AxisVertical {
+			err := &core.ConfigError{Reason: core.ErrInvalidAxis, Path: path}
+			logError(ctx, logger, path, "stack.axis", err)
+			return false, err
+		}
+
+		painted := false
+		for _, slot := range node.Slots {
+			slotPainted, err := paintLayoutWithPath(ctx, slot, r, buf, slot.Path, failures)
+			if err != nil {
+				logError(ctx, logger, path, "stack.render", err)
+				return false, err
+			}
+			painted = painted || slotPainted
+		}
+		return painted, nil
+	case engine.NodeFrame:
+		out, err := renderFrameNode(ctx, node, r)
+		if err := paintFrameResult(buf, node, out, err, failures); err != nil {
+			return false, err
+		}
+		return true, nil
+	default:
+		err := &core.ConfigError{Reason: core.ErrUnknownSpec, Path: path}
+		logError(ctx, logger, path, "dispatch", err)
+		return false, err
+	}
+}
+
+// renderFrameNode renders and checks the output of an arranged frame node,
+// logging its duration when the logger is enabled.
+func renderFrameNode[KID KeelID](ctx context.Context, node engine.LayoutNode[KID], r *Renderer[KID]) (string, error) {
+	logger := rendererLogger(r)
+	if err := canceled(ctx, node.Path); err != nil {
+		logError(ctx, logger, node.Path, "frame.context", err)
+		return "", err
+	}
+	if node.Frame == nil {
+		err := &core.ConfigError{Reason: core.ErrUnknownSpec, Path: node.Path}
+		logError(ctx, logger, node.Path, "dispatch", err)
+		return "", err
+	}
+	var start time.Time
+	timed := logging.Enabled(logger, slog.LevelDebug)
+	if timed {
+		start = time.Now()
+	}
+
+	size := Size{Width: node.Rect.Width, Height: node.Rect.Height}
+	out, err := renderFrameWithPath(ctx, node.Frame, r, size, node.Path)
+	if err == nil {
+		out, err = checkDimensions(ctx, r, out, node.Rect, node.Path, node.Frame.ID())
+	}
+
+	if timed {
+		logEvent(
+			ctx,
+			logger,
+			node.Path,
+			logging.EventFrameTiming,
+			slog.Any("id", node.Frame.ID()),
+			slog.Duration("duration", time.Since(start)),
+		)
+	}
+	return out, err
+}
+
+// paintFrameResult paints a rendered frame, or its placeholder when failures
+// collects frame errors. Cancellation always aborts.
+func paintFrameResult[KID KeelID](buf *cellbuf.Buffer, node engine.LayoutNode[KID], out string, err error, failures *[]error) error {
+	if err != nil {
+		var canceledErr *CanceledError
+		if failures == nil || errors.As(err, &canceledErr) {
+			return err
+		}
+		*failures = append(*failures, convertError(err))
+		out = placeholder(err, node.Rect)
+	}
+	paintFrame(buf, out, node.Rect)
+	return nil
+}
+
+// paintFrame paints rendered frame output at rect, growing the buffer when the
+// output extends past it.
+func paintFrame(buf *cellbuf.Buffer, out string, rect engine.Rect) {
+	width, height := rect.Width, rect.Height
+	outWidth, outHeight := gloss.Size(out)
+	width = max(width, outWidth)
+	height = max(height, outHeight)
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if rect.X+width > buf.Width() || rect.Y+height > buf.Height() {
+		buf.Resize(max(buf.Width(), rect.X+width), max(buf.Height(), rect.Y+height))
+	}
+	cellbuf.SetContentRect(buf, out, cellbuf.Rect(rect.X, rect.Y, width, height))
+}
+
+// resetStyle is the SGR reset lipgloss writes, so lines still end the way
+// lipgloss output does.
+const resetStyle = "\x1b[0m"
+
+// bufferString serializes a buffer with one line per row, preserving
+// trailing blanks and resetting styles and hyperlinks at the end of each line.
+// At least rows lines are written, so a zero-width layout still has its
+// height in empty lines.
+func bufferString(buf *cellbuf.Buffer, rows int) string {
+	var b strings.Builder
+	b.Grow(buf.Width() * buf.Height())
+	for y := range max(rows, len(buf.Lines)) {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		if y < len(buf.Lines) {
+			writeLine(&b, buf.Lines[y])
+		}
+	}
+	return b.String()
+}
+
+// writeLine writes cells with the minimal SGR and hyperlink changes, starting
+// from and returning to the default pen. Runs with the same style are merged
+// even across frames, so the SGR bytes differ from the providers' own output.
+// Writes to b cannot fail.
+func writeLine(b io.StringWriter, line cellbuf.Line) {
+	var pen cellbuf.Style
+	var link cellbuf.Link
+	for _, cell := range line {
+		cell = cellOrBlank(cell)
+		if cell.Width == 0 {
+			// Placeholder for the trailing columns of a wide cell.
+			continue
+		}
+		if cell.Style.Empty() && !pen.Empty() {
+			b.WriteString(resetStyle)
+			pen.Reset()
+		}
+		if !cell.Style.Equal(&pen) {
+			b.WriteString(cell.Style.DiffSequence(pen))
+			pen = cell.Style
+		}
+		if cell.Link != link {
+			if link.URL != "" {
+				b.WriteString(ansi.ResetHyperlink())
+			}
+			if cell.Link.URL != "" {
+				b.WriteString(ansi.SetHyperlink(cell.Link.URL, cell.Link.Params))
+			}
+			link = cell.Link
+		}
+		b.WriteString(cell.String())
+	}
+	if link.URL != "" {
+		b.WriteString(ansi.ResetHyperlink())
+	}
+	if !pen.Empty() {
+		b.WriteString(resetStyle)
+	}
+}
+
+func cellOrBlank(cell *cellbuf.Cell) *cellbuf.Cell {
+	if cell == nil {
+		return &cellbuf.BlankCell
+	}
+	return cell
+}
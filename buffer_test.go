@@ -0,0 +1,208 @@
+package keel
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/muesli/termenv"
+)
+
+func TestRenderBufferPaintsAtRects(t *testing.T) {
+	layout := Col(FlexUnit(),
+		Clip(Fixed(1), "top"),
+		Row(FlexUnit(),
+			Clip(Fixed(2), "left"),
+			Clip(FlexUnit(), "right"),
+		),
+	)
+	renderer := NewRenderer(layout, nil, func(id string, _ FrameInfo) (string, error) {
+		return id, nil
+	})
+
+	buf, err := renderer.RenderBuffer(Size{Width: 6, Height: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Width() != 6 || buf.Height() != 2 {
+		t.Fatalf("expected 6x2 buffer, got %dx%d", buf.Width(), buf.Height())
+	}
+	cases := []struct {
+		x, y int
+		want rune
+	}{
+		{0, 0, 't'},
+		{0, 1, 'l'},
+		{1, 1, 'e'},
+		{2, 1, 'r'},
+		{5, 1, 'h'},
+	}
+	for _, tc := range cases {
+		if got := buf.Cell(tc.x, tc.y).Rune; got != tc.want {
+			t.Fatalf("expected %q at %d,%d, got %q", tc.want, tc.x, tc.y, got)
+		}
+	}
+}
+
+func TestRenderBufferErrors(t *testing.T) {
+	var renderer *Renderer[string]
+	if _, err := renderer.RenderBuffer(Size{Width: 1, Height: 1}); !errors.Is(err, ErrRendererMissing) {
+		t.Fatalf("expected ErrRendererMissing, got %v", err)
+	}
+	renderer = NewRenderer[string](nil, nil, nil)
+	if _, err := renderer.RenderBuffer(Size{Width: 1, Height: 1}); !errors.Is(err, ErrSpecMissing) {
+		t.Fatalf("expected ErrSpecMissing, got %v", err)
+	}
+	renderer = NewRenderer[string](Row(FlexUnit(), nil), nil, nil)
+	if _, err := renderer.RenderBuffer(Size{Width: 1, Height: 1}); !errors.Is(err, ErrConfigurationInvalid) {
+		t.Fatalf("expected ErrConfigurationInvalid, got %v", err)
+	}
+}
+
+func TestRenderNestedEmptyStackIsBlank(t *testing.T) {
+	layout := Row(FlexUnit(),
+		Exact(Fixed(2), "a"),
+		Row(FlexUnit()),
+	)
+	renderer := NewRenderer(layout, nil, makeContentProvider("ab"))
+
+	got, err := renderer.Render(Size{Width: 4, Height: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "ab  \n    "
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRenderBufferOverflowGrows(t *testing.T) {
+	renderer := NewRenderer(Overflow(FlexUnit(), "a"), nil, makeContentProvider("abcd"))
+
+	buf, err := renderer.RenderBuffer(Size{Width: 2, Height: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Width() != 2 || buf.Height() != 2 {
+		t.Fatalf("expected 2x2 buffer, got %dx%d", buf.Width(), buf.Height())
+	}
+}
+
+func TestRenderWideRunes(t *testing.T) {
+	layout := Row(FlexUnit(),
+		Exact(Fixed(2), "a"),
+		Exact(Fixed(2), "b"),
+	)
+	renderer := NewRenderer(layout, nil, func(id string, _ FrameInfo) (string, error) {
+		if id == "a" {
+			return "世", nil
+		}
+		return "ok", nil
+	})
+
+	got, err := renderer.Render(Size{Width: 4, Height: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "世ok" {
+		t.Fatalf("expected %q, got %q", "世ok", got)
+	}
+}
+
+func TestBufferStringStyles(t *testing.T) {
+	buf := cellbuf.NewBuffer(4, 2)
+	cellbuf.SetContent(buf, "\x1b[1mab\x1b[0mc\n\x1b[4md\x1b[0m")
+
+	want := "\x1b[1mab\x1b[0mc \n\x1b[4md\x1b[0m   "
+	if got := bufferString(buf, 2); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+// TestRenderGolden pins Render output for content styles, SGR resets and
+// zero-width sizes.
+func TestRenderGolden(t *testing.T) {
+	layout := Row(FlexUnit(), Clip(FlexUnit(), "a"), Clip(FlexUnit(), "b"))
+	renderer := NewRenderer(layout, nil, func(id string, _ FrameInfo) (string, error) {
+		if id == "a" {
+			return "\x1b[1;31mab\x1b[0mc\n\x1b[4md\x1b[0m", nil
+		}
+		return "\x1b[32mgreen\x1b[0m", nil
+	})
+
+	cases := []struct {
+		size Size
+		want string
+	}{
+		{Size{Width: 8, Height: 2}, "\x1b[1;31mab\x1b[0mc \x1b[32mgree\x1b[0m\n\x1b[4md\x1b[0m       "},
+		{Size{Width: 0, Height: 2}, "\n"},
+		{Size{Width: 0, Height: 3}, "\n\n"},
+		{Size{Width: 0, Height: 0}, ""},
+	}
+	for _, tc := range cases {
+		got, err := renderer.Render(tc.size)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", tc.size, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%v: expected %q, got %q", tc.size, tc.want, got)
+		}
+		var b strings.Builder
+		if err := renderer.RenderTo(&b, tc.size); err != nil {
+			t.Fatalf("%v: unexpected error: %v", tc.size, err)
+		}
+		if b.String() != tc.want {
+			t.Fatalf("%v: expected RenderTo %q, got %q", tc.size, tc.want, b.String())
+		}
+	}
+}
+
+// TestRenderGoldenBorders pins the serialized SGR of bordered and colored
+// frames side by side: equal styles merge across frames, and style changes
+// are minimal transitions rather than a reset per frame.
+func TestRenderGoldenBorders(t *testing.T) {
+	lr := gloss.NewRenderer(io.Discard)
+	lr.SetColorProfile(termenv.TrueColor)
+	rounded := lr.NewStyle().Border(gloss.RoundedBorder()).BorderForeground(gloss.Color("5"))
+	filled := lr.NewStyle().Bold(true).Background(gloss.Color("4"))
+	normal := lr.NewStyle().Border(gloss.NormalBorder()).BorderForeground(gloss.Color("5"))
+	layout := Row(FlexUnit(),
+		Clip(FlexUnit(), "a"),
+		Clip(FlexUnit(), "b"),
+		Clip(FlexUnit(), "c"),
+		Clip(FlexUnit(), "d"),
+	)
+	renderer := NewRenderer(layout, func(id string) *gloss.Style {
+		switch id {
+		case "c":
+			return &filled
+		case "d":
+			return &normal
+		}
+		return &rounded
+	}, func(id string, _ FrameInfo) (string, error) {
+		return id + "x", nil
+	})
+
+	want := "\x1b[35m╭───╮╭───╮\x1b[39;44;1mcx\x1b[22m   \x1b[35;49m┌───┐\x1b[0m\n" +
+		"\x1b[35m│\x1b[0max \x1b[35m││\x1b[0mbx \x1b[35m│\x1b[39;44m     \x1b[35;49m│\x1b[0mdx \x1b[35m│\x1b[0m\n" +
+		"\x1b[35m╰───╯╰───╯\x1b[39;44m     \x1b[35;49m└───┘\x1b[0m"
+	size := Size{Width: 20, Height: 3}
+	got, err := renderer.Render(size)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	var b strings.Builder
+	if err := renderer.RenderTo(&b, size); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.String() != want {
+		t.Fatalf("expected RenderTo %q, got %q", want, b.String())
+	}
+}
@@ -0,0 +1,97 @@
+package keel
+
+import (
+	"context"
+	"log/slog"
+
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/trippwill/keel/logging"
+)
+
+// VersionProvider opts frames into output caching. It returns the current
+// version of a frame's content, such as a counter or an etag, and whether the
+// frame should be cached at all. A cached frame reuses its last rendered
+// output while its [FrameInfo], [FrameState], style pointer and version are
+// unchanged.
+type VersionProvider[KID KeelID] func(id KID) (version string, ok bool)
+
+type cachedFrame struct {
+	info    FrameInfo
+	state   FrameState
+	style   *gloss.Style
+	version string
+	debug   bool
+	out     string
+}
+
+// SetVersionProvider sets the provider that opts frames into output caching.
+// Nil disables caching. Replacing it clears the cache.
+func (r *Renderer[KID]) SetVersionProvider(p VersionProvider[KID]) {
+	if r == nil {
+		return
+	}
+	r.version = p
+	r.clearFrameCache()
+}
+
+// InvalidateFrame evicts the cached output of frame id, so its provider runs
+// on the next render even if its version is unchanged.
+func (r *Renderer[KID]) InvalidateFrame(id KID) {
+	if r == nil {
+		return
+	}
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+	delete(r.cache, id)
+}
+
+func (r *Renderer[KID]) clearFrameCache() {
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+	r.cache = nil
+}
+
+// frameCacheKey returns the cache key for frame id, and false when the
+// frame is not cached.
+func frameCacheKey[KID KeelID](r *Renderer[KID], id KID, info FrameInfo, style *gloss.Style) (cachedFrame, bool) {
+	if r.version == nil {
+		return cachedFrame{}, false
+	}
+	version, ok := r.version(id)
+	if !ok {
+		return cachedFrame{}, false
+	}
+	return cachedFrame{info: info, state: r.State(id), style: style, version: version, debug: r.config.Debug()}, true
+}
+
+// cachedOutput returns the cached output of frame id when its entry matches
+// key, logging the hit or miss.
+func (r *Renderer[KID]) cachedOutput(ctx context.Context, path string, id KID, key cachedFrame) (string, bool) {
+	r.cacheMu.Lock()
+	entry, found := r.cache[id]
+	r.cacheMu.Unlock()
+	out := entry.out
+	entry.out = ""
+	hit := found && entry == key
+
+	logEvent(
+		ctx,
+		rendererLogger(r),
+		path,
+		logging.EventFrameCache,
+		slog.Any("id", id),
+		slog.Bool("hit", hit),
+		slog.String("version", key.version),
+	)
+	return out, hit
+}
+
+func (r *Renderer[KID]) storeOutput(id KID, key cachedFrame, out string) {
+	key.out = out
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+	if r.cache == nil {
+		r.cache = map[KID]cachedFrame{}
+	}
+	r.cache[id] = key
+}
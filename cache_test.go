@@ -0,0 +1,121 @@
+package keel
+
+import (
+	"log/slog"
+	"strconv"
+	"testing"
+
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/trippwill/keel/logging"
+)
+
+func TestFrameCacheFollowsState(t *testing.T) {
+	var renderer *Renderer[string]
+	renderer = NewRenderer(Exact(Fixed(8), "a"), nil, func(id string, _ FrameInfo) (string, error) {
+		return renderer.State(id).String(), nil
+	})
+	renderer.SetVersionProvider(func(string) (string, bool) { return "v", true })
+	size := Size{Width: 8, Height: 1}
+
+	if out, err := renderer.Render(size); err != nil || out != "none    " {
+		t.Fatalf("unexpected first render %q, %v", out, err)
+	}
+	renderer.SetState("a", StateActive)
+	if out, err := renderer.Render(size); err != nil || out != "active  " {
+		t.Fatalf("expected state change to miss the cache, got %q, %v", out, err)
+	}
+}
+
+func TestFrameCache(t *testing.T) {
+	layout := Row(FlexUnit(),
+		Exact(Fixed(3), "a"),
+		Exact(Fixed(3), "b"),
+	)
+	calls := map[string]int{}
+	versions := map[string]int{}
+	renderer := NewRenderer(layout, nil, func(id string, _ FrameInfo) (string, error) {
+		calls[id]++
+		return strconv.Itoa(calls[id]), nil
+	})
+	renderer.SetVersionProvider(func(id string) (string, bool) {
+		return strconv.Itoa(versions[id]), id == "a"
+	})
+	handler, entries := newCaptureHandler()
+	renderer.Config().SetLogger(slog.New(handler))
+	size := Size{Width: 6, Height: 1}
+
+	render := func(want string) {
+		t.Helper()
+		out, err := renderer.Render(size)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != want {
+			t.Fatalf("expected %q, got %q", want, out)
+		}
+	}
+
+	render("1  1  ")
+	render("1  2  ")
+	versions["a"]++
+	render("2  3  ")
+	renderer.InvalidateFrame("a")
+	render("3  4  ")
+
+	var hits []bool
+	for _, entry := range *entries {
+		if entry.attrs["event"] == string(logging.EventFrameCache) {
+			hits = append(hits, entry.attrs["hit"].(bool))
+		}
+	}
+	want := []bool{false, true, false, false}
+	if len(hits) != len(want) {
+		t.Fatalf("expected %d frame.cache events, got %v", len(want), hits)
+	}
+	for i := range want {
+		if hits[i] != want[i] {
+			t.Fatalf("expected hits %v, got %v", want, hits)
+		}
+	}
+}
+
+func TestFrameCacheKey(t *testing.T) {
+	calls := 0
+	first := gloss.NewStyle()
+	second := gloss.NewStyle()
+	style := &first
+	renderer := NewRenderer(Exact(Fixed(2), "a"), func(string) *gloss.Style {
+		return style
+	}, func(string, FrameInfo) (string, error) {
+		calls++
+		return "x", nil
+	})
+	renderer.SetVersionProvider(func(string) (string, bool) { return "", true })
+
+	renders := []func(){
+		func() {},
+		func() {},
+		func() { style = &second },
+		func() { renderer.Config().SetDebug(true) },
+		func() { renderer.Config().SetDebug(false) },
+		func() {
+			renderer.SetContentProvider(func(string, FrameInfo) (string, error) { calls++; return "y", nil })
+		},
+	}
+	wantCalls := []int{1, 1, 2, 2, 3, 4}
+	for i, change := range renders {
+		change()
+		if _, err := renderer.Render(Size{Width: 2, Height: 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != wantCalls[i] {
+			t.Fatalf("render %d: expected %d provider calls, got %d", i, wantCalls[i], calls)
+		}
+	}
+	if _, err := renderer.Render(Size{Width: 3, Height: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(renderer.cache) != 1 {
+		t.Fatalf("expected one entry per frame, got %d", len(renderer.cache))
+	}
+}
@@ -0,0 +1,98 @@
+package keel
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/trippwill/keel/core"
+	"github.com/trippwill/keel/engine"
+)
+
+type frameResult struct {
+	out      string
+	err      error
+	panicked bool
+	value    any
+}
+
+// paintLayoutConcurrent renders every frame of the layout with up to workers
+// goroutines, then paints the results in tree order. Errors are handled in
+// tree order, as with a serial render. A provider panic that is not recovered
+// stops the workers and is re-raised on the calling goroutine once they exit,
+// so callers can recover it as they would with a serial render.
+func paintLayoutConcurrent[KID KeelID](ctx context.Context, root engine.LayoutNode[KID], r *Renderer[KID], buf *cellbuf.Buffer, workers int, failures *[]error) (bool, error) {
+	var nodes []engine.LayoutNode[KID]
+	if err := collectFrameNodes(ctx, root, r, &nodes); err != nil {
+		return false, err
+	}
+
+	results := make([]frameResult, len(nodes))
+	var next atomic.Int64
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	for range min(workers, len(nodes)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			i := -1
+			defer func() {
+				if value := recover(); value != nil {
+					results[i] = frameResult{panicked: true, value: value}
+					stop.Store(true)
+				}
+			}()
+			for !stop.Load() {
+				i = int(next.Add(1)) - 1
+				if i >= len(nodes) {
+					return
+				}
+				out, err := renderFrameNode(ctx, nodes[i], r)
+				results[i] = frameResult{out: out, err: err}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		if result.panicked {
+			panic(result.value)
+		}
+	}
+	for i, result := range results {
+		if err := paintFrameResult(buf, nodes[i], result.out, result.err, failures); err != nil {
+			return false, err
+		}
+	}
+	return len(nodes) > 0, nil
+}
+
+// collectFrameNodes appends the frame nodes under node in tree order,
+// validating stacks as a serial render would.
+func collectFrameNodes[KID KeelID](ctx context.Context, node engine.LayoutNode[KID], r *Renderer[KID], nodes *[]engine.LayoutNode[KID]) error {
+	switch node.Kind {
+	case engine.NodeStack:
+		if len(node.Slots) == 0 {
+			return nil
+		}
+		if node.Axis != core.AxisHorizontal && node.Axis != core.AxisVertical {
+			err := &core.ConfigError{Reason: core.ErrInvalidAxis, Path: node.Path}
+			logError(ctx, rendererLogger(r), node.Path, "stack.axis", err)
+			return err
+		}
+		for _, slot := range node.Slots {
+			if err := collectFrameNodes(ctx, slot, r, nodes); err != nil {
+				return err
+			}
+		}
+		return nil
+	case engine.NodeFrame:
+		*nodes = append(*nodes, node)
+		return nil
+	default:
+		err := &core.ConfigError{Reason: core.ErrUnknownSpec, Path: node.Path}
+		logError(ctx, rendererLogger(r), node.Path, "dispatch", err)
+		return err
+	}
+}
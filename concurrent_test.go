@@ -0,0 +1,133 @@
+package keel
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/trippwill/keel/logging"
+)
+
+func concurrentLayout() Spec {
+	return Col(FlexUnit(),
+		Clip(Fixed(1), "header"),
+		Row(FlexUnit(),
+			Wrap(FlexMin(1, 6), "nav"),
+			Col(FlexUnit(),
+				Clip(FlexUnit(), "a"),
+				Clip(FlexUnit(), "b"),
+				Clip(FlexUnit(), "c"),
+			),
+		),
+		Clip(Fixed(1), "footer"),
+	)
+}
+
+func TestRenderConcurrentMatchesSerial(t *testing.T) {
+	style := gloss.NewStyle().Border(gloss.NormalBorder())
+	styles := func(id string) *gloss.Style {
+		if id == "nav" {
+			return &style
+		}
+		return nil
+	}
+	content := func(id string, info FrameInfo) (string, error) {
+		return fmt.Sprintf("%s %dx%d", id, info.ContentWidth, info.ContentHeight), nil
+	}
+	size := Size{Width: 30, Height: 12}
+
+	want, err := NewRenderer(concurrentLayout(), styles, content).Render(size)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, workers := range []int{2, 3, 16} {
+		renderer := NewRenderer(concurrentLayout(), styles, content)
+		renderer.Config().SetConcurrency(workers)
+		got, err := renderer.Render(size)
+		if err != nil {
+			t.Fatalf("workers %d: unexpected error: %v", workers, err)
+		}
+		if got != want {
+			t.Fatalf("workers %d: expected\n%s\ngot\n%s", workers, want, got)
+		}
+	}
+}
+
+func TestRenderConcurrentRunsInParallel(t *testing.T) {
+	var started sync.WaitGroup
+	started.Add(2)
+	release := make(chan struct{})
+	go func() {
+		started.Wait()
+		close(release)
+	}()
+
+	renderer := NewRenderer(Row(FlexUnit(),
+		Clip(FlexUnit(), "a"),
+		Clip(FlexUnit(), "b"),
+	), nil, func(id string, _ FrameInfo) (string, error) {
+		started.Done()
+		select {
+		case <-release:
+			return id, nil
+		case <-time.After(5 * time.Second):
+			return "", errors.New("providers did not run concurrently")
+		}
+	})
+	renderer.Config().SetConcurrency(2)
+	out, err := renderer.Render(Size{Width: 2, Height: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "ab" {
+		t.Fatalf("expected %q, got %q", "ab", out)
+	}
+}
+
+func TestRenderConcurrentFirstErrorInTreeOrder(t *testing.T) {
+	errA := errors.New("a failed")
+	errC := errors.New("c failed")
+	renderer := NewRenderer(concurrentLayout(), nil, func(id string, _ FrameInfo) (string, error) {
+		switch id {
+		case "a":
+			time.Sleep(20 * time.Millisecond)
+			return "", errA
+		case "c":
+			return "", errC
+		}
+		return "", nil
+	})
+	renderer.Config().SetConcurrency(4)
+	_, err := renderer.Render(Size{Width: 30, Height: 12})
+	if !errors.Is(err, errA) {
+		t.Fatalf("expected first error in tree order, got %v", err)
+	}
+}
+
+func TestRenderLogsFrameTiming(t *testing.T) {
+	handler, entries := newCaptureHandler()
+	renderer := NewRenderer(concurrentLayout(), nil, makeContentProvider(""))
+	renderer.Config().SetLogger(slog.New(handler))
+	renderer.Config().SetConcurrency(3)
+	if _, err := renderer.Render(Size{Width: 30, Height: 12}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	timed := map[any]bool{}
+	for _, entry := range *entries {
+		if entry.attrs["event"] != string(logging.EventFrameTiming) {
+			continue
+		}
+		if _, ok := entry.attrs["duration"].(time.Duration); !ok {
+			t.Fatalf("expected duration attr, got %+v", entry.attrs)
+		}
+		timed[entry.attrs["id"]] = true
+	}
+	if len(timed) != 6 {
+		t.Fatalf("expected timings for 6 frames, got %v", timed)
+	}
+}
@@ -2,11 +2,44 @@ package keel
 
 import "log/slog"
 
+// DimensionCheck selects how rendered output dimensions are verified.
+type DimensionCheck uint8
+
+const (
+	// DimensionCheckOff performs no verification. This is the default.
+	DimensionCheckOff DimensionCheck = iota
+	// DimensionCheckStrict fails rendering with a [DimensionMismatchError]
+	// when a frame or the whole output is not exactly its allocated size.
+	DimensionCheckStrict
+	// DimensionCheckRepair pads or truncates mismatched output to its
+	// allocated size and logs a warning instead of failing.
+	DimensionCheckRepair
+)
+
+// ErrorMode selects how frame render errors are handled.
+type ErrorMode uint8
+
+const (
+	// ErrorModeAbort fails the whole render on the first frame error.
+	// This is the default.
+	ErrorModeAbort ErrorMode = iota
+	// ErrorModePlaceholder paints a placeholder showing the error in the rect
+	// of each failing frame and returns the partial output together with all
+	// frame errors joined in tree order. Cancellation and spec errors still
+	// abort.
+	ErrorModePlaceholder
+)
+
 // Config stores shared render settings like logging and debug state.
 // It is safe to share a single config across multiple renderers.
 type Config struct {
-	logger *slog.Logger
-	debug  bool
+	logger      *slog.Logger
+	debug       bool
+	dimensions  DimensionCheck
+	concurrency int
+	errorMode   ErrorMode
+	recover     bool
+	layoutCache int
 }
 
 // NewConfig returns a new renderer configuration with the default settings.
@@ -38,10 +71,109 @@ func (c *Config) Debug() bool {
 	return c.debug
 }
 
-// SetDebug sets debug rendering.
+// SetDebug sets debug rendering. Debug rendering applies [DebugContent]
+// beneath any middleware added with [Renderer.Use].
 func (c *Config) SetDebug(debug bool) {
 	if c == nil {
 		return
 	}
 	c.debug = debug
 }
+
+// DimensionCheck reports how rendered output dimensions are verified.
+func (c *Config) DimensionCheck() DimensionCheck {
+	if c == nil {
+		return DimensionCheckOff
+	}
+	return c.dimensions
+}
+
+// SetDimensionCheck sets how rendered output dimensions are verified.
+// Every frame output is checked against its allocated rect, and the whole
+// output against the render size: the line count must equal the height and
+// every line's display width must equal the width. Stacks are painted into
+// the cell buffer at their rects rather than rendered to strings, so they are
+// covered by the check of the whole output. Allocations with zero height are
+// not checked.
+func (c *Config) SetDimensionCheck(check DimensionCheck) {
+	if c == nil {
+		return
+	}
+	c.dimensions = check
+}
+
+// Concurrency reports how many frames are rendered concurrently.
+func (c *Config) Concurrency() int {
+	if c == nil {
+		return 0
+	}
+	return c.concurrency
+}
+
+// SetConcurrency sets how many frames are rendered concurrently after
+// arranging. Values of 1 or less render frames serially, in tree order.
+//
+// With n > 1, content and style providers are called from up to n goroutines
+// and must be safe for concurrent use. Output is assembled in tree order, so
+// it is identical to a serial render, and the first error in tree order is
+// returned.
+func (c *Config) SetConcurrency(n int) {
+	if c == nil {
+		return
+	}
+	c.concurrency = n
+}
+
+// ErrorMode reports how frame render errors are handled.
+func (c *Config) ErrorMode() ErrorMode {
+	if c == nil {
+		return ErrorModeAbort
+	}
+	return c.errorMode
+}
+
+// SetErrorMode sets how frame render errors are handled.
+func (c *Config) SetErrorMode(mode ErrorMode) {
+	if c == nil {
+		return
+	}
+	c.errorMode = mode
+}
+
+// RecoverPanics reports whether provider panics are recovered.
+func (c *Config) RecoverPanics() bool {
+	if c == nil {
+		return false
+	}
+	return c.recover
+}
+
+// SetRecoverPanics sets whether panics in content and style providers are
+// recovered and returned as a [ProviderPanicError] for the frame, instead of
+// unwinding through Render. Recovered panics are logged with their stack as a
+// provider.panic event.
+func (c *Config) SetRecoverPanics(enabled bool) {
+	if c == nil {
+		return
+	}
+	c.recover = enabled
+}
+
+// LayoutCacheSize reports how many arranged layouts a renderer keeps.
+func (c *Config) LayoutCacheSize() int {
+	if c == nil || c.layoutCache <= 0 {
+		return DefaultLayoutCacheSize
+	}
+	return c.layoutCache
+}
+
+// SetLayoutCacheSize sets how many arranged layouts, keyed by size, a
+// renderer keeps in its least-recently-used layout cache. Raise it when one
+// renderer serves several sizes, such as multiple sessions or previews.
+// Values below 1 restore [DefaultLayoutCacheSize].
+func (c *Config) SetLayoutCacheSize(n int) {
+	if c == nil {
+		return
+	}
+	c.layoutCache = n
+}
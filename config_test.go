@@ -14,6 +14,18 @@ func TestConfigNilReceiver(t *testing.T) {
 	if config.Debug() {
 		t.Fatalf("expected debug false")
 	}
+	if config.DimensionCheck() != DimensionCheckOff {
+		t.Fatalf("expected dimension check off")
+	}
+	config.SetDimensionCheck(DimensionCheckStrict)
+	if config.Concurrency() != 0 {
+		t.Fatalf("expected serial rendering")
+	}
+	config.SetConcurrency(4)
+	if config.ErrorMode() != ErrorModeAbort {
+		t.Fatalf("expected abort error mode")
+	}
+	config.SetErrorMode(ErrorModePlaceholder)
 	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
 	config.SetLogger(logger)
 	config.SetDebug(true)
@@ -30,4 +42,20 @@ func TestConfigSetters(t *testing.T) {
 	if !config.Debug() {
 		t.Fatalf("expected debug true")
 	}
+	config.SetDimensionCheck(DimensionCheckRepair)
+	if config.DimensionCheck() != DimensionCheckRepair {
+		t.Fatalf("expected dimension check repair")
+	}
+	config.SetConcurrency(4)
+	if config.Concurrency() != 4 {
+		t.Fatalf("expected concurrency 4")
+	}
+	config.SetErrorMode(ErrorModePlaceholder)
+	if config.ErrorMode() != ErrorModePlaceholder {
+		t.Fatalf("expected placeholder error mode")
+	}
+	config.SetRecoverPanics(true)
+	if !config.RecoverPanics() {
+		t.Fatalf("expected recover panics")
+	}
 }
@@ -0,0 +1,127 @@
+package keel
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type traceKey struct{}
+
+type contextHandler struct {
+	traces []any
+}
+
+func (h *contextHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *contextHandler) Handle(ctx context.Context, _ slog.Record) error {
+	h.traces = append(h.traces, ctx.Value(traceKey{}))
+	return nil
+}
+
+func (h *contextHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *contextHandler) WithGroup(string) slog.Handler { return h }
+
+func TestRenderContextCanceledBeforeRender(t *testing.T) {
+	renderer := NewRenderer(Clip(FlexUnit(), "a"), nil, makeContentProvider("a"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := renderer.RenderContext(ctx, Size{Width: 1, Height: 1})
+	var canceledErr *CanceledError
+	if !errors.As(err, &canceledErr) {
+		t.Fatalf("expected CanceledError, got %v", err)
+	}
+	if !errors.Is(err, ErrRenderCanceled) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled sentinels, got %v", err)
+	}
+	if renderer.hasLayout {
+		t.Fatalf("expected no arrange after cancellation")
+	}
+}
+
+func TestRenderContextStopsAtNextFrame(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls []string
+	renderer := NewRenderer[string](Row(FlexUnit(),
+		Clip(FlexUnit(), "a"),
+		Clip(FlexUnit(), "b"),
+	), nil, nil)
+	renderer.SetContentProviderCtx(func(ctx context.Context, id string, _ FrameInfo) (string, error) {
+		calls = append(calls, id)
+		cancel()
+		return id, nil
+	})
+
+	_, err := renderer.RenderContext(ctx, Size{Width: 2, Height: 1})
+	var canceledErr *CanceledError
+	if !errors.As(err, &canceledErr) {
+		t.Fatalf("expected CanceledError, got %v", err)
+	}
+	if canceledErr.Path != "/1" || canceledErr.ID != nil {
+		t.Fatalf("expected cancellation before /1, got %+v", canceledErr)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("expected one provider call, got %v", calls)
+	}
+}
+
+func TestRenderContextDeadline(t *testing.T) {
+	renderer := NewRenderer[string](Clip(FlexUnit(), "slow"), nil, nil)
+	renderer.SetContentProviderCtx(func(ctx context.Context, id string, _ FrameInfo) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	renderer.Config().SetConcurrency(2)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	_, err := renderer.RenderContext(ctx, Size{Width: 4, Height: 1})
+	var canceledErr *CanceledError
+	if !errors.As(err, &canceledErr) {
+		t.Fatalf("expected CanceledError, got %v", err)
+	}
+	if canceledErr.ID != "slow" || canceledErr.Path != "/" {
+		t.Fatalf("expected frame slow at /, got %+v", canceledErr)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRenderContextProviderFallback(t *testing.T) {
+	renderer := NewRenderer(Clip(FlexUnit(), "a"), nil, makeContentProvider("plain"))
+	renderer.SetContentProviderCtx(func(context.Context, string, FrameInfo) (string, error) {
+		return "ctx", nil
+	})
+	if out, _ := renderer.Render(Size{Width: 5, Height: 1}); out != "ctx  " {
+		t.Fatalf("expected context provider output, got %q", out)
+	}
+	renderer.SetContentProviderCtx(nil)
+	if out, _ := renderer.Render(Size{Width: 5, Height: 1}); out != "plain" {
+		t.Fatalf("expected plain provider output, got %q", out)
+	}
+}
+
+func TestRenderContextFlowsIntoLogger(t *testing.T) {
+	handler := &contextHandler{}
+	renderer := NewRenderer(Row(FlexUnit(), Clip(FlexUnit(), "a")), nil, makeContentProvider("a"))
+	renderer.Config().SetLogger(slog.New(handler))
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace-1")
+
+	if _, err := renderer.RenderContext(ctx, Size{Width: 1, Height: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(handler.traces) == 0 {
+		t.Fatalf("expected log records")
+	}
+	for _, trace := range handler.traces {
+		if trace != "trace-1" {
+			t.Fatalf("expected render context in every record, got %v", handler.traces)
+		}
+	}
+}
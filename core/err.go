@@ -16,6 +16,8 @@ var (
 	ErrInvalidAxis = errors.New("invalid axis")
 	// ErrNilSlot indicates a nil slot entry.
 	ErrNilSlot = errors.New("nil slot")
+	// ErrSlotIndex indicates a slot index out of range.
+	ErrSlotIndex = errors.New("slot index out of range")
 	// ErrInvalidTotal indicates an invalid total allocation.
 	ErrInvalidTotal = errors.New("invalid total")
 	// ErrEmptyExtents indicates a missing set of extents.
@@ -36,11 +38,15 @@ var (
 
 // ExtentTooSmallError includes context about which allocation failed.
 // It wraps ErrExtentTooSmall for errors.Is checks.
+// Path is the slash-delimited layout path of the failing node and ID is the
+// frame ID when the failure is attributable to a frame.
 type ExtentTooSmallError struct {
 	Axis       Axis
 	Need, Have int
 	Source     string
 	Reason     string
+	Path       string
+	ID         any
 }
 
 func (e *ExtentTooSmallError) Error() string {
@@ -53,10 +59,11 @@ func (e *ExtentTooSmallError) Error() string {
 		reason = " (" + e.Reason + ")"
 	}
 	return fmt.Sprintf(
-		"extent too small on %s axis%s%s: need %d, have %d",
+		"extent too small on %s axis%s%s%s: need %d, have %d",
 		e.Axis,
 		source,
 		reason,
+		location(e.Path, nil),
 		e.Need,
 		e.Have,
 	)
@@ -68,15 +75,19 @@ func (e *ExtentTooSmallError) Unwrap() error {
 
 // ConfigError wraps a configuration issue with a specific reason.
 // It unwraps to the underlying reason and matches ErrConfigurationInvalid.
+// Path and ID locate the failing node when known.
 type ConfigError struct {
 	Reason error
+	Path   string
+	ID     any
 }
 
 func (e *ConfigError) Error() string {
+	loc := location(e.Path, e.ID)
 	if e.Reason == nil {
-		return ErrConfigurationInvalid.Error()
+		return ErrConfigurationInvalid.Error() + loc
 	}
-	return fmt.Sprintf("%s: %s", ErrConfigurationInvalid, e.Reason)
+	return fmt.Sprintf("%s%s: %s", ErrConfigurationInvalid, loc, e.Reason)
 }
 
 func (e *ConfigError) Unwrap() error {
@@ -95,16 +106,19 @@ func (e *ConfigError) Is(target error) bool {
 
 // ExtentError describes a validation issue for a specific extent.
 // It wraps ErrConfigurationInvalid and the underlying reason.
+// Path is the layout path of the stack that owns the extent.
 type ExtentError struct {
 	Index  int
 	Reason error
+	Path   string
 }
 
 func (e *ExtentError) Error() string {
+	loc := location(e.Path, nil)
 	if e.Reason == nil {
-		return fmt.Sprintf("%s: extent %d", ErrConfigurationInvalid, e.Index)
+		return fmt.Sprintf("%s: extent %d%s", ErrConfigurationInvalid, e.Index, loc)
 	}
-	return fmt.Sprintf("%s: extent %d: %s", ErrConfigurationInvalid, e.Index, e.Reason)
+	return fmt.Sprintf("%s: extent %d%s: %s", ErrConfigurationInvalid, e.Index, loc, e.Reason)
 }
 
 func (e *ExtentError) Unwrap() error {
@@ -123,16 +137,19 @@ func (e *ExtentError) Is(target error) bool {
 
 // SlotError describes a validation issue for a specific slot.
 // It wraps ErrConfigurationInvalid and the underlying reason.
+// Path is the layout path of the stack that owns the slot.
 type SlotError struct {
 	Index  int
 	Reason error
+	Path   string
 }
 
 func (e *SlotError) Error() string {
+	loc := location(e.Path, nil)
 	if e.Reason == nil {
-		return fmt.Sprintf("%s: slot %d", ErrConfigurationInvalid, e.Index)
+		return fmt.Sprintf("%s: slot %d%s", ErrConfigurationInvalid, e.Index, loc)
 	}
-	return fmt.Sprintf("%s: slot %d: %s", ErrConfigurationInvalid, e.Index, e.Reason)
+	return fmt.Sprintf("%s: slot %d%s: %s", ErrConfigurationInvalid, e.Index, loc, e.Reason)
 }
 
 func (e *SlotError) Unwrap() error {
@@ -148,3 +165,15 @@ func (e *SlotError) Is(target error) bool {
 	}
 	return errors.Is(e.Reason, target)
 }
+
+// location formats an optional " at <path> (frame <id>)" suffix.
+func location(path string, id any) string {
+	loc := ""
+	if path != "" {
+		loc = " at " + path
+	}
+	if id != nil {
+		loc += fmt.Sprintf(" (frame %v)", id)
+	}
+	return loc
+}
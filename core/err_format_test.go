@@ -62,3 +62,44 @@ func TestSlotErrorFormat(t *testing.T) {
 		t.Fatalf("expected %q, got %q", want, err.Error())
 	}
 }
+
+func TestErrorFormatWithPath(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "extent too small",
+			err:  &ExtentTooSmallError{Axis: AxisHorizontal, Need: 4, Have: 2, Source: "frame a", Reason: "content", Path: "/1/0", ID: "a"},
+			want: "extent too small on Horizontal axis for frame a (content) at /1/0: need 4, have 2",
+		},
+		{
+			name: "config",
+			err:  &ConfigError{Reason: ErrInvalidAxis, Path: "/2"},
+			want: "configuration invalid at /2: invalid axis",
+		},
+		{
+			name: "config with id",
+			err:  &ConfigError{Path: "/0", ID: "a"},
+			want: "configuration invalid at /0 (frame a)",
+		},
+		{
+			name: "extent",
+			err:  &ExtentError{Index: 1, Reason: ErrInvalidExtentUnits, Path: "/0"},
+			want: "configuration invalid: extent 1 at /0: invalid extent units",
+		},
+		{
+			name: "slot",
+			err:  &SlotError{Index: 2, Reason: ErrNilSlot, Path: "/0/1"},
+			want: "configuration invalid: slot 2 at /0/1: nil slot",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.err.Error() != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, tc.err.Error())
+			}
+		})
+	}
+}
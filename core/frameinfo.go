@@ -6,4 +6,5 @@ type FrameInfo struct {
 	ContentWidth, ContentHeight int     // Inner content box size
 	FrameWidth, FrameHeight     int     // Total frame size (padding + border + margin)
 	Fit                         FitMode // Fit mode for content
+	Focused                     bool    // Whether the frame has keyboard focus
 }
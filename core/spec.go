@@ -23,3 +23,26 @@ type StackSpec interface {
 	Len() int                    // Number of slots in the stack
 	Slot(index int) (Spec, bool) // Slot access (ok=false when out of range); must be stable during an arrange pass
 }
+
+// Focusable is implemented by frame specs that can opt out of keyboard focus.
+// Frames that do not implement it are focusable.
+type Focusable interface {
+	Focusable() bool
+}
+
+// Versioned is implemented by specs that can change after construction.
+// Generation must increase whenever the spec changes, and stacks include the
+// generations of their slots so that changes bubble up to the root. Renderers
+// re-arrange when the generation of their spec changes.
+type Versioned interface {
+	Generation() uint64
+}
+
+// Generation returns the generation of a [Versioned] spec, or zero for specs
+// that never change.
+func Generation(spec Spec) uint64 {
+	if v, ok := spec.(Versioned); ok {
+		return v.Generation()
+	}
+	return 0
+}
@@ -0,0 +1,100 @@
+package keel
+
+import (
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+// diffMergeGap is the number of unchanged cells between two changed runs on a
+// line below which the runs are written as one, since a cursor move costs
+// about as many bytes.
+const diffMergeGap = 4
+
+// RenderDiff renders the stored spec at the given size and writes only the
+// cells that changed since the previous RenderDiff call, positioned with
+// absolute cursor movement sequences.
+//
+// Output is addressed from the top-left corner of the terminal (row 1,
+// column 1), so it is intended for full-screen use such as the alternate
+// screen. The first call, and any call where the rendered dimensions change,
+// clears the screen and redraws everything. Every written run starts and ends
+// with the default pen, so styles do not bleed between updated regions, and
+// runs never split a wide character.
+//
+// On error nothing is written and the previous frame is kept, except with
+// [ErrorModePlaceholder], where the partial frame is written and the joined
+// frame errors are returned.
+func (r *Renderer[KID]) RenderDiff(size Size, w io.Writer) error {
+	buf, frameErr := r.RenderBuffer(size)
+	if buf == nil {
+		return frameErr
+	}
+
+	var b strings.Builder
+	prev := r.previous
+	if prev == nil || prev.Width() != buf.Width() || prev.Height() != buf.Height() {
+		b.WriteString(ansi.EraseEntireScreen)
+		for y, line := range buf.Lines {
+			b.WriteString(ansi.CursorPosition(1, y+1))
+			writeLine(&b, line)
+		}
+	} else {
+		for y, line := range buf.Lines {
+			writeLineDiff(&b, prev.Lines[y], line, y)
+		}
+	}
+
+	if b.Len() > 0 {
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	r.previous = buf
+	return frameErr
+}
+
+// ResetDiff discards the previous frame so the next [Renderer.RenderDiff]
+// redraws the whole screen. Call it after anything else writes to the terminal.
+func (r *Renderer[KID]) ResetDiff() {
+	if r == nil {
+		return
+	}
+	r.previous = nil
+}
+
+// writeLineDiff writes the runs of cells in line y that differ from prev.
+func writeLineDiff(b *strings.Builder, prev, line cellbuf.Line, y int) {
+	width := len(line)
+	for x := 0; x < width; {
+		if cellOrBlank(prev[x]).Equal(cellOrBlank(line[x])) {
+			x++
+			continue
+		}
+
+		start := x
+		last := x
+		for j := x + 1; j < width && j-last <= diffMergeGap; j++ {
+			if !cellOrBlank(prev[j]).Equal(cellOrBlank(line[j])) {
+				last = j
+			}
+		}
+
+		// Never start on the trailing placeholder of a wide cell, and never
+		// end part-way through one.
+		for start > 0 && cellOrBlank(line[start]).Width == 0 {
+			start--
+		}
+		end := last + 1
+		for i := start; i <= last; i++ {
+			end = max(end, i+cellOrBlank(line[i]).Width)
+		}
+		end = min(end, width)
+
+		b.WriteString(ansi.CursorPosition(start+1, y+1))
+		writeLine(b, line[start:end])
+		x = end
+	}
+}
@@ -0,0 +1,178 @@
+package keel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderDiffFirstFrameRedraws(t *testing.T) {
+	renderer := NewRenderer(Clip(FlexUnit(), "a"), nil, makeContentProvider("ab"))
+
+	var b strings.Builder
+	if err := renderer.RenderDiff(Size{Width: 3, Height: 2}, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := ansi.EraseEntireScreen +
+		ansi.CursorPosition(1, 1) + "ab " +
+		ansi.CursorPosition(1, 2) + "   "
+	if got := b.String(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRenderDiffWritesChangedCells(t *testing.T) {
+	text := "abcdefghij"
+	renderer := NewRenderer(Clip(FlexUnit(), "a"), nil, func(string, FrameInfo) (string, error) {
+		return text, nil
+	})
+	size := Size{Width: 10, Height: 1}
+
+	var b strings.Builder
+	if err := renderer.RenderDiff(size, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b.Reset()
+	if err := renderer.RenderDiff(size, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.Len() != 0 {
+		t.Fatalf("expected no output for an unchanged frame, got %q", b.String())
+	}
+
+	text = "aXcdefghiY"
+	b.Reset()
+	if err := renderer.RenderDiff(size, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := ansi.CursorPosition(2, 1) + "X" + ansi.CursorPosition(10, 1) + "Y"
+	if got := b.String(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	text = "aZcZfghiY"
+	b.Reset()
+	if err := renderer.RenderDiff(size, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = ansi.CursorPosition(2, 1) + "ZcZfghiY "
+	if got := b.String(); got != want {
+		t.Fatalf("expected merged run %q, got %q", want, got)
+	}
+}
+
+func TestRenderDiffWideCells(t *testing.T) {
+	text := "ab世d"
+	renderer := NewRenderer(Clip(FlexUnit(), "a"), nil, func(string, FrameInfo) (string, error) {
+		return text, nil
+	})
+	size := Size{Width: 5, Height: 1}
+
+	var b strings.Builder
+	if err := renderer.RenderDiff(size, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text = "ab界d"
+	b.Reset()
+	if err := renderer.RenderDiff(size, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := ansi.CursorPosition(3, 1) + "界"
+	if got := b.String(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	text = "abxyd"
+	b.Reset()
+	if err := renderer.RenderDiff(size, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = ansi.CursorPosition(3, 1) + "xy"
+	if got := b.String(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRenderDiffStylesDoNotBleed(t *testing.T) {
+	text := "ab  cd"
+	renderer := NewRenderer(Clip(FlexUnit(), "a"), nil, func(string, FrameInfo) (string, error) {
+		return text, nil
+	})
+	size := Size{Width: 12, Height: 1}
+
+	var b strings.Builder
+	if err := renderer.RenderDiff(size, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text = "\x1b[1mXb\x1b[m  cd     \x1b[4mZ\x1b[m"
+	b.Reset()
+	if err := renderer.RenderDiff(size, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := ansi.CursorPosition(1, 1) + "\x1b[1mXb\x1b[0m" +
+		ansi.CursorPosition(12, 1) + "\x1b[4mZ\x1b[0m"
+	if got := b.String(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRenderDiffResize(t *testing.T) {
+	renderer := NewRenderer(Clip(FlexUnit(), "a"), nil, makeContentProvider("a"))
+
+	var b strings.Builder
+	if err := renderer.RenderDiff(Size{Width: 2, Height: 1}, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b.Reset()
+	if err := renderer.RenderDiff(Size{Width: 3, Height: 1}, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(b.String(), ansi.EraseEntireScreen) {
+		t.Fatalf("expected full redraw after resize, got %q", b.String())
+	}
+
+	renderer.ResetDiff()
+	b.Reset()
+	if err := renderer.RenderDiff(Size{Width: 3, Height: 1}, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(b.String(), ansi.EraseEntireScreen) {
+		t.Fatalf("expected full redraw after ResetDiff, got %q", b.String())
+	}
+}
+
+func TestRenderDiffErrorKeepsPrevious(t *testing.T) {
+	fail := false
+	renderer := NewRenderer(Clip(FlexUnit(), "a"), nil, func(string, FrameInfo) (string, error) {
+		if fail {
+			return "", errors.New("boom")
+		}
+		return "a", nil
+	})
+	size := Size{Width: 2, Height: 1}
+
+	var b strings.Builder
+	if err := renderer.RenderDiff(size, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fail = true
+	b.Reset()
+	if err := renderer.RenderDiff(size, &b); err == nil {
+		t.Fatalf("expected error")
+	}
+	if b.Len() != 0 {
+		t.Fatalf("expected no output on error, got %q", b.String())
+	}
+	fail = false
+	if err := renderer.RenderDiff(size, &b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.Len() != 0 {
+		t.Fatalf("expected diff against the last good frame, got %q", b.String())
+	}
+}
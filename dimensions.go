@@ -0,0 +1,86 @@
+package keel
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/trippwill/keel/engine"
+	"github.com/trippwill/keel/logging"
+)
+
+// checkDimensions verifies rendered output against its allocated rect
+// according to the configured [DimensionCheck]. id is nil for stacks.
+func checkDimensions[KID KeelID](ctx context.Context, r *Renderer[KID], out string, rect engine.Rect, path string, id any) (string, error) {
+	check := r.config.DimensionCheck()
+	if check == DimensionCheckOff || rect.Height <= 0 {
+		return out, nil
+	}
+	want := Size{Width: rect.Width, Height: rect.Height}
+	got, ok := measureOutput(out, want.Width)
+	if ok && got == want {
+		return out, nil
+	}
+
+	logger := rendererLogger(r)
+	err := &DimensionMismatchError{Path: path, ID: id, Want: want, Got: got}
+	if check != DimensionCheckRepair {
+		logError(ctx, logger, path, "render.dimensions", err)
+		return "", err
+	}
+	logging.LogEventContext(
+		ctx,
+		logger,
+		slog.LevelWarn,
+		logging.EventRenderRepair,
+		path,
+		slog.Any("id", id),
+		slog.Int("want_width", want.Width),
+		slog.Int("want_height", want.Height),
+		slog.Int("got_width", got.Width),
+		slog.Int("got_height", got.Height),
+	)
+	return repairOutput(out, want), nil
+}
+
+// measureOutput returns the line count and the display width of the first
+// line that is not exactly width cells wide (or width when all lines match).
+func measureOutput(out string, width int) (Size, bool) {
+	lines := strings.Split(out, "\n")
+	got := Size{Width: width, Height: len(lines)}
+	for _, line := range lines {
+		if w := ansi.StringWidth(line); w != width {
+			got.Width = w
+			return got, false
+		}
+	}
+	return got, true
+}
+
+// repairOutput pads or truncates out to exactly size.
+func repairOutput(out string, size Size) string {
+	lines := strings.Split(out, "\n")
+	if len(lines) > size.Height {
+		lines = lines[:size.Height]
+	}
+	for i, line := range lines {
+		lines[i] = fitLine(line, size.Width)
+	}
+	for len(lines) < size.Height {
+		lines = append(lines, strings.Repeat(" ", size.Width))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func fitLine(line string, width int) string {
+	w := ansi.StringWidth(line)
+	if w > width {
+		line = ansi.Truncate(line, width, "")
+		w = ansi.StringWidth(line)
+	}
+	if w < width {
+		line += strings.Repeat(" ", width-w)
+	}
+	return line
+}
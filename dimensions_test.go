@@ -0,0 +1,124 @@
+package keel
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/trippwill/keel/logging"
+)
+
+func TestDimensionCheckStrictOverflow(t *testing.T) {
+	layout := Row(FlexUnit(),
+		Exact(Fixed(1), "a"),
+		Overflow(FlexUnit(), "b"),
+	)
+	renderer := NewRenderer(layout, nil, func(id string, _ FrameInfo) (string, error) {
+		if id == "b" {
+			return "abcd", nil
+		}
+		return "", nil
+	})
+	renderer.Config().SetDimensionCheck(DimensionCheckStrict)
+
+	_, err := renderer.Render(Size{Width: 3, Height: 1})
+	var mismatch *DimensionMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected DimensionMismatchError, got %v", err)
+	}
+	if !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("expected ErrDimensionMismatch")
+	}
+	if mismatch.Path != "/1" || mismatch.ID != "b" {
+		t.Fatalf("expected frame b at /1, got %+v", mismatch)
+	}
+	want := Size{Width: 2, Height: 1}
+	got := Size{Width: 2, Height: 2}
+	if mismatch.Want != want || mismatch.Got != got {
+		t.Fatalf("expected want %+v got %+v, got %+v", want, got, mismatch)
+	}
+}
+
+func TestDimensionCheckStrictPasses(t *testing.T) {
+	layout := Col(FlexUnit(),
+		Clip(Fixed(1), "a"),
+		Row(FlexUnit(),
+			Wrap(FlexUnit(), "b"),
+			Exact(Fixed(3), "c"),
+		),
+	)
+	renderer := NewRenderer(layout, nil, makeContentProvider("x"))
+	renderer.Config().SetDimensionCheck(DimensionCheckStrict)
+
+	if _, err := renderer.Render(Size{Width: 8, Height: 4}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDimensionCheckStrictEmptyStack(t *testing.T) {
+	renderer := NewRenderer[string](Row(FlexUnit()), nil, nil)
+	renderer.Config().SetDimensionCheck(DimensionCheckStrict)
+
+	_, err := renderer.Render(Size{Width: 3, Height: 2})
+	var mismatch *DimensionMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected DimensionMismatchError, got %v", err)
+	}
+	if mismatch.Path != "/" || mismatch.ID != nil {
+		t.Fatalf("expected root stack, got %+v", mismatch)
+	}
+}
+
+func TestDimensionCheckRepair(t *testing.T) {
+	handler, entries := newCaptureHandler()
+	layout := Row(FlexUnit(),
+		Overflow(Fixed(2), "a"),
+		Clip(FlexUnit(), "b"),
+	)
+	renderer := NewRenderer(layout, nil, makeContentProvider("abcd"))
+	renderer.Config().SetDimensionCheck(DimensionCheckRepair)
+	renderer.Config().SetLogger(slog.New(handler))
+
+	got, err := renderer.Render(Size{Width: 4, Height: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "abab"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	var repairs []logEntry
+	for _, entry := range *entries {
+		if entry.attrs["event"] == string(logging.EventRenderRepair) {
+			repairs = append(repairs, entry)
+		}
+	}
+	if len(repairs) != 1 {
+		t.Fatalf("expected 1 repair, got %d", len(repairs))
+	}
+	if repairs[0].level != slog.LevelWarn || repairs[0].attrs["path"] != "/0" {
+		t.Fatalf("expected warning for /0, got %+v", repairs[0])
+	}
+}
+
+func TestRepairOutput(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		size Size
+		want string
+	}{
+		{"pad", "a", Size{Width: 3, Height: 2}, "a  \n   "},
+		{"truncate", "abcd\nefgh\nijkl", Size{Width: 2, Height: 2}, "ab\nef"},
+		{"wide rune", "世界", Size{Width: 3, Height: 1}, "世 "},
+		{"zero height", "abc", Size{Width: 3, Height: 0}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := repairOutput(tc.in, tc.size); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
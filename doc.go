@@ -2,7 +2,9 @@
 //
 // Rendering is top-down: each stack splits its allocated space along an
 // axis and passes the resulting width/height to its slots. Frames render
-// content and optional lipgloss styles inside that allocation. Rendering is
+// content and optional lipgloss styles inside that allocation, and each frame
+// is painted into a cell buffer at its allocated rect (see
+// [Renderer.RenderBuffer]) before the buffer is serialized once. Rendering is
 // strict by default: if frames or content do not fit, rendering fails with an
 // extent-too-small error unless the selected fit mode permits fitting. Keel does not perform
 // intrinsic measurement,
@@ -10,6 +12,8 @@
 //
 // Error surfaces are small and stable: size issues return [ExtentTooSmallError],
 // while configuration issues return [SpecError] wrapping [ErrConfigurationInvalid].
+// Both carry the slash-delimited layout path of the failing node (e.g. "/0/1")
+// and the frame ID when the failure is attributable to a frame.
 //
 // For repeated renders, store a spec on a [Renderer] and call [Renderer.Render].
 // The renderer caches the arranged layout for the last size; call [Renderer.Invalidate]
@@ -29,7 +29,12 @@ func ArrangeStack(total int, stack core.StackSpec) ([]int, int, error) {
 // - Slice of [core.ExtentConstraint] for each slot
 // - Error, if any slot is nil
 func GetStackExtents(stack core.StackSpec) ([]core.ExtentConstraint, error) {
-	extents := make([]core.ExtentConstraint, stack.Len())
+	return appendStackExtents(nil, stack)
+}
+
+// appendStackExtents is [GetStackExtents] reusing the storage of dst.
+func appendStackExtents(dst []core.ExtentConstraint, stack core.StackSpec) ([]core.ExtentConstraint, error) {
+	extents := grow(dst, stack.Len())
 	for i := range extents {
 		slot, ok := stack.Slot(i)
 		if !ok || slot == nil {
@@ -54,21 +59,41 @@ func GetStackExtents(stack core.StackSpec) ([]core.ExtentConstraint, error) {
 //   - Minimum required total (int)
 //   - Error, if allocation fails
 func ArrangeExtents(total int, extents []core.ExtentConstraint) ([]int, int, error) {
+	return arrangeExtents(total, extents, nil)
+}
+
+// arrangeExtents implements [ArrangeExtents], recording each allocation step
+// in ex when it is non-nil.
+func arrangeExtents(total int, extents []core.ExtentConstraint, ex *Explanation) ([]int, int, error) {
+	return arrangeExtentsInto(make([]int, len(extents)), total, extents, ex, &extentScratch{})
+}
+
+// extentScratch holds working slices that [arrangeExtentsInto] reuses.
+type extentScratch struct {
+	flexSpecs []flexSpec
+	active    []int
+}
+
+// arrangeExtentsInto implements [arrangeExtents], writing sizes into the
+// given slice, which must have one element per extent.
+func arrangeExtentsInto(sizes []int, total int, extents []core.ExtentConstraint, ex *Explanation, s *extentScratch) ([]int, int, error) {
 	if total < 0 {
 		return nil, 0, &core.ConfigError{Reason: core.ErrInvalidTotal}
 	}
 
-	count := len(extents)
-	if count <= 0 {
-		return []int{}, 0, nil
+	if len(extents) == 0 {
+		return sizes, 0, nil
 	}
 
-	sizes := make([]int, count)
 	required, flexUnits, hasFlex, hasFlexMax, err := seedSizes(sizes, extents)
 	if err != nil {
 		return nil, required, err
 	}
 
+	if ex != nil {
+		ex.seed(total, required, extents, sizes)
+	}
+
 	if required > total {
 		return nil, required, core.ErrExtentTooSmall
 	}
@@ -78,22 +103,27 @@ func ArrangeExtents(total int, extents []core.ExtentConstraint) ([]int, int, err
 	if !hasFlex {
 		if leftover > 0 {
 			sizes[len(sizes)-1] += leftover
+			if ex != nil {
+				ex.Slots[len(sizes)-1].Leftover = leftover
+			}
 		}
+		ex.finish(sizes)
 		return sizes, required, nil
 	}
 
 	if leftover > 0 {
 		if hasFlexMax {
-			flexSpecs := collectFlexSpecs(extents)
-			remaining := distributeFlexWithMax(sizes, flexSpecs, leftover)
+			s.flexSpecs = appendFlexSpecs(s.flexSpecs[:0], extents)
+			remaining := distributeFlexWithMax(sizes, s.flexSpecs, leftover, ex, s)
 			if remaining > 0 {
-				distributeFlexIgnoringMax(sizes, extents, flexUnits, remaining)
+				distributeFlexIgnoringMax(sizes, extents, flexUnits, remaining, ex, true)
 			}
 		} else {
-			distributeFlexIgnoringMax(sizes, extents, flexUnits, leftover)
+			distributeFlexIgnoringMax(sizes, extents, flexUnits, leftover, ex, false)
 		}
 	}
 
+	ex.finish(sizes)
 	return sizes, required, nil
 }
 
@@ -147,8 +177,7 @@ func seedSizes(sizes []int, extents []core.ExtentConstraint) (int, int, bool, bo
 	return required, flexUnits, hasFlex, hasFlexMax, nil
 }
 
-func collectFlexSpecs(extents []core.ExtentConstraint) []flexSpec {
-	flexSpecs := make([]flexSpec, 0, len(extents))
+func appendFlexSpecs(flexSpecs []flexSpec, extents []core.ExtentConstraint) []flexSpec {
 	for i, spec := range extents {
 		if spec.Kind != core.ExtentFlex {
 			continue
@@ -162,7 +191,7 @@ func collectFlexSpecs(extents []core.ExtentConstraint) []flexSpec {
 	return flexSpecs
 }
 
-func distributeFlexWithMax(sizes []int, flexSpecs []flexSpec, leftover int) int {
+func distributeFlexWithMax(sizes []int, flexSpecs []flexSpec, leftover int, ex *Explanation, s *extentScratch) int {
 	if leftover <= 0 {
 		return 0
 	}
@@ -191,20 +220,21 @@ func distributeFlexWithMax(sizes []int, flexSpecs []flexSpec, leftover int) int
 	}
 
 	if amount > 0 {
-		remaining += distributeFlexCapped(sizes, flexSpecs, amount)
+		remaining += distributeFlexCapped(sizes, flexSpecs, amount, ex, s)
 	}
 
 	return remaining
 }
 
-func distributeFlexCapped(sizes []int, flexSpecs []flexSpec, amount int) int {
+func distributeFlexCapped(sizes []int, flexSpecs []flexSpec, amount int, ex *Explanation, s *extentScratch) int {
 	remaining := amount
-	active := make([]int, 0, len(flexSpecs))
+	active := s.active[:0]
 	for i, spec := range flexSpecs {
 		if spec.max == 0 || sizes[spec.index] < spec.max {
 			active = append(active, i)
 		}
 	}
+	s.active = active
 
 	for remaining > 0 && len(active) > 0 {
 		totalUnits := 0
@@ -221,11 +251,17 @@ func distributeFlexCapped(sizes []int, flexSpecs []flexSpec, amount int) int {
 			}
 			cap := maxFlexAdd(spec.max, sizes[spec.index], remaining)
 			if add > cap {
+				if ex != nil {
+					ex.Slots[spec.index].Capped += add - cap
+				}
 				add = cap
 			}
 			if add > 0 {
 				sizes[spec.index] += add
 				distributed += add
+				if ex != nil {
+					ex.Slots[spec.index].Share += add
+				}
 			}
 		}
 		remaining -= distributed
@@ -242,6 +278,9 @@ func distributeFlexCapped(sizes []int, flexSpecs []flexSpec, amount int) int {
 				}
 				sizes[spec.index]++
 				remaining--
+				if ex != nil {
+					ex.Slots[spec.index].Remainder++
+				}
 			}
 		}
 
@@ -268,7 +307,9 @@ func maxFlexAdd(max int, size int, remaining int) int {
 	return max - size
 }
 
-func distributeFlexIgnoringMax(sizes []int, extents []core.ExtentConstraint, flexUnits int, leftover int) {
+// distributeFlexIgnoringMax distributes leftover by flex units without max caps.
+// When overflow is set the cells are recorded in ex as soft-max overflow.
+func distributeFlexIgnoringMax(sizes []int, extents []core.ExtentConstraint, flexUnits int, leftover int, ex *Explanation, overflow bool) {
 	if leftover <= 0 {
 		return
 	}
@@ -284,6 +325,13 @@ func distributeFlexIgnoringMax(sizes []int, extents []core.ExtentConstraint, fle
 		add := leftover * spec.Units / flexUnits
 		sizes[i] += add
 		remainder -= add
+		if ex != nil {
+			if overflow {
+				ex.Slots[i].Overflow += add
+			} else {
+				ex.Slots[i].Share += add
+			}
+		}
 	}
 
 	if remainder > 0 {
@@ -294,6 +342,22 @@ func distributeFlexIgnoringMax(sizes []int, extents []core.ExtentConstraint, fle
 			}
 			sizes[i]++
 			remainder--
+			if ex != nil {
+				if overflow {
+					ex.Slots[i].Overflow++
+				} else {
+					ex.Slots[i].Remainder++
+				}
+			}
 		}
 	}
 }
+
+// grow returns s resized to n elements, reusing its storage when it is large
+// enough. Reused elements keep their previous values.
+func grow[T any](s []T, n int) []T {
+	if cap(s) < n {
+		return make([]T, n)
+	}
+	return s[:n]
+}
@@ -57,6 +57,30 @@ func BenchmarkArrangeStack(b *testing.B) {
 	}
 }
 
+func BenchmarkArrangerExtents(b *testing.B) {
+	for _, count := range []int{3, 8, 32, 128} {
+		b.Run(fmt.Sprintf("n=%d", count), func(b *testing.B) {
+			slots, required := benchSlots(count)
+			extents, err := GetStackExtents(&benchStack{slots: slots})
+			if err != nil {
+				b.Fatal(err)
+			}
+			arranger := NewArranger[string](nil)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			total := required
+			for b.Loop() {
+				total++
+				if _, _, err := arranger.ArrangeExtents(total, extents); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 func benchSlots(count int) ([]core.Spec, int) {
 	slots := make([]core.Spec, count)
 	required := 0
@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/trippwill/keel/core"
+)
+
+// Arranger arranges specs like [Arrange] while reusing its working memory
+// across calls. Once it has arranged a spec, arranging the same tree again,
+// at any size, allocates nothing unless logging is enabled or arranging
+// fails. Use it to arrange many candidate sizes, for example in a min-size
+// search.
+//
+// Layouts and sizes returned by an Arranger share its storage and are only
+// valid until its next call. An Arranger is not safe for concurrent use.
+type Arranger[KID core.KeelID] struct {
+	logger  *slog.Logger
+	reuse   bool
+	scratch extentScratch
+	extents []core.ExtentConstraint
+	result  []int
+	paths   []pathEntry
+	next    int
+
+	sizes     []int
+	nodes     []LayoutNode[KID]
+	sizesNeed int
+	nodesNeed int
+}
+
+type pathEntry struct {
+	parent string
+	index  int
+	path   string
+}
+
+// NewArranger returns an arranger that logs like [Arrange] to logger.
+func NewArranger[KID core.KeelID](logger *slog.Logger) *Arranger[KID] {
+	return &Arranger[KID]{logger: logger, reuse: true}
+}
+
+// Arrange is [engine.Arrange] reusing the arranger's storage.
+func (a *Arranger[KID]) Arrange(spec core.Spec, size core.Size) (Layout[KID], error) {
+	return a.ArrangeContext(context.Background(), spec, size)
+}
+
+// ArrangeContext is [engine.ArrangeContext] reusing the arranger's storage.
+func (a *Arranger[KID]) ArrangeContext(ctx context.Context, spec core.Spec, size core.Size) (Layout[KID], error) {
+	resetArena(&a.sizes, &a.sizesNeed)
+	resetArena(&a.nodes, &a.nodesNeed)
+	a.next = 0
+	return a.arrange(ctx, spec, size)
+}
+
+// ArrangeExtents is [engine.ArrangeExtents] reusing the arranger's storage.
+func (a *Arranger[KID]) ArrangeExtents(total int, extents []core.ExtentConstraint) ([]int, int, error) {
+	a.result = grow(a.result, len(extents))
+	return arrangeExtentsInto(a.result, total, extents, nil, &a.scratch)
+}
+
+func (a *Arranger[KID]) arrange(ctx context.Context, spec core.Spec, size core.Size) (Layout[KID], error) {
+	rect := Rect{X: 0, Y: 0, Width: size.Width, Height: size.Height}
+	root, err := a.arrangeWithPath(ctx, spec, rect, "/")
+	if err != nil {
+		return Layout[KID]{}, err
+	}
+	return Layout[KID]{
+		Width:  size.Width,
+		Height: size.Height,
+		Root:   root,
+	}, nil
+}
+
+// sizeSlice returns storage for the slot sizes of a stack.
+func (a *Arranger[KID]) sizeSlice(n int) []int {
+	if !a.reuse {
+		return make([]int, n)
+	}
+	return carve(&a.sizes, &a.sizesNeed, n)
+}
+
+// nodeSlice returns storage for the slot nodes of a stack.
+func (a *Arranger[KID]) nodeSlice(n int) []LayoutNode[KID] {
+	if !a.reuse {
+		return make([]LayoutNode[KID], n)
+	}
+	return carve(&a.nodes, &a.nodesNeed, n)
+}
+
+// path returns the path of slot index under parent, reusing the string built
+// for the same node of the previous arrange.
+func (a *Arranger[KID]) path(parent string, index int) string {
+	if !a.reuse {
+		return appendPath(parent, index)
+	}
+	k := a.next
+	a.next++
+	if k < len(a.paths) {
+		entry := &a.paths[k]
+		if entry.index != index || entry.parent != parent {
+			*entry = pathEntry{parent: parent, index: index, path: appendPath(parent, index)}
+		}
+		return entry.path
+	}
+	a.paths = append(a.paths, pathEntry{parent: parent, index: index, path: appendPath(parent, index)})
+	return a.paths[k].path
+}
+
+// carve returns n elements from the free end of arena, moving to new storage
+// when it is full, and records the demand for [resetArena].
+func carve[T any](arena *[]T, need *int, n int) []T {
+	*need += n
+	s := *arena
+	if cap(s)-len(s) < n {
+		s = make([]T, 0, max(2*cap(s), n))
+	}
+	start := len(s)
+	s = s[:start+n]
+	*arena = s
+	return s[start : start+n : start+n]
+}
+
+// resetArena empties arena, growing it to the demand of the previous pass so
+// that the next pass fits in one block.
+func resetArena[T any](arena *[]T, need *int) {
+	if cap(*arena) < *need {
+		*arena = make([]T, 0, *need)
+	} else {
+		*arena = (*arena)[:0]
+	}
+	*need = 0
+}
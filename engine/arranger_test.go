@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/trippwill/keel/core"
+)
+
+func arrangerSpec() core.Spec {
+	capped := core.ExtentConstraint{Kind: core.ExtentFlex, Units: 1, MinCells: 1, MaxCells: 4}
+	return testStack{
+		ExtentConstraint: flex(1),
+		axis:             core.AxisVertical,
+		slots: []core.Spec{
+			testFrame{ExtentConstraint: fixed(2), id: "header"},
+			testStack{
+				ExtentConstraint: flex(1),
+				axis:             core.AxisHorizontal,
+				slots: []core.Spec{
+					testFrame{ExtentConstraint: capped, id: "nav"},
+					testFrame{ExtentConstraint: flex(2), id: "body"},
+					testStack{
+						ExtentConstraint: capped,
+						axis:             core.AxisVertical,
+						slots: []core.Spec{
+							testFrame{ExtentConstraint: flex(1), id: "a"},
+							testFrame{ExtentConstraint: flex(1), id: "b"},
+						},
+					},
+				},
+			},
+			testFrame{ExtentConstraint: fixed(1), id: "status"},
+		},
+	}
+}
+
+func TestArrangerMatchesArrange(t *testing.T) {
+	spec := arrangerSpec()
+	arranger := NewArranger[string](nil)
+	for _, size := range []core.Size{{Width: 20, Height: 8}, {Width: 9, Height: 5}, {Width: 40, Height: 12}, {Width: 20, Height: 8}} {
+		want, err := Arrange[string](spec, size, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := arranger.Arrange(spec, size)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("size %+v: expected %+v, got %+v", size, want, got)
+		}
+	}
+
+	if _, err := arranger.Arrange(spec, core.Size{Width: 20, Height: 2}); err == nil {
+		t.Fatalf("expected extent error")
+	}
+	got, err := arranger.Arrange(spec, core.Size{Width: 20, Height: 8})
+	want, _ := Arrange[string](spec, core.Size{Width: 20, Height: 8}, nil)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected arranger to recover after an error, got %+v, %v", got, err)
+	}
+
+	sizes, required, err := arranger.ArrangeExtents(10, []core.ExtentConstraint{fixed(2), flex(1), flex(1)})
+	if err != nil || required != 2 || !reflect.DeepEqual(sizes, []int{2, 4, 4}) {
+		t.Fatalf("unexpected extents %v, %d, %v", sizes, required, err)
+	}
+}
+
+func TestArrangerAllocations(t *testing.T) {
+	spec := arrangerSpec()
+	arranger := NewArranger[string](nil)
+	width := 20
+	arrange := func() {
+		width++
+		if width > 40 {
+			width = 20
+		}
+		if _, err := arranger.Arrange(spec, core.Size{Width: width, Height: 8}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	arrange()
+	arrange()
+	if allocs := testing.AllocsPerRun(100, arrange); allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+	extents := []core.ExtentConstraint{fixed(2), flex(1), {Kind: core.ExtentFlex, Units: 1, MaxCells: 3}}
+	if allocs := testing.AllocsPerRun(100, func() { _, _, _ = arranger.ArrangeExtents(20, extents) }); allocs != 0 {
+		t.Fatalf("expected no allocations for extents, got %v", allocs)
+	}
+}
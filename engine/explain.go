@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/trippwill/keel/core"
+)
+
+// Explanation describes how [ArrangeExtents] reached each slot size.
+type Explanation struct {
+	Total    int               // Total cells distributed
+	Required int               // Minimum required total (sum of seeds)
+	Slots    []SlotExplanation // Per-slot allocation steps
+}
+
+// SlotExplanation breaks a slot size into the allocation steps that produced it.
+//
+// Size = Seed + Share + Remainder + Overflow + Leftover.
+type SlotExplanation struct {
+	Index     int
+	Extent    core.ExtentConstraint
+	Seed      int // Fixed units or MinCells reserved before distribution
+	Share     int // Cells from proportional flex distribution
+	Capped    int // Proportional cells withheld by MaxCells and redistributed
+	Remainder int // Cells from rounding remainder distribution
+	Overflow  int // Cells from soft-max overflow after all caps were reached
+	Leftover  int // Leftover cells given to the last slot when no slot flexes
+	Size      int // Final size
+}
+
+// ExplainExtents distributes total across extents like [ArrangeExtents] and
+// reports how each slot size was reached.
+//
+// When the extents do not fit, the returned explanation still carries the
+// seeds and required total alongside the error.
+func ExplainExtents(total int, extents []core.ExtentConstraint) (Explanation, error) {
+	ex := Explanation{Total: total}
+	_, required, err := arrangeExtents(total, extents, &ex)
+	ex.Required = required
+	return ex, err
+}
+
+// ExplainStack explains the allocation of total cells across a stack.
+// The slot extents are determined by calling Slot(i).Extent() on the stack.
+func ExplainStack(total int, stack core.StackSpec) (Explanation, error) {
+	extents, err := GetStackExtents(stack)
+	if err != nil {
+		return Explanation{Total: total}, err
+	}
+	return ExplainExtents(total, extents)
+}
+
+// String formats the explanation as a table with one row per slot.
+func (e Explanation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "total %d, required %d\n", e.Total, e.Required)
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "slot\textent\tseed\tshare\tcapped\tremainder\toverflow\tleftover\tsize")
+	for _, slot := range e.Slots {
+		fmt.Fprintf(
+			w,
+			"%d\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n",
+			slot.Index,
+			formatExtent(slot.Extent),
+			slot.Seed,
+			slot.Share,
+			slot.Capped,
+			slot.Remainder,
+			slot.Overflow,
+			slot.Leftover,
+			slot.Size,
+		)
+	}
+	_ = w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func formatExtent(extent core.ExtentConstraint) string {
+	out := fmt.Sprintf("%s(%d)", extent.Kind, extent.Units)
+	if extent.Kind == core.ExtentFlex && (extent.MinCells > 0 || extent.MaxCells > 0) {
+		out += fmt.Sprintf("[%d,%d]", extent.MinCells, extent.MaxCells)
+	}
+	return out
+}
+
+func (e *Explanation) seed(total int, required int, extents []core.ExtentConstraint, sizes []int) {
+	e.Total = total
+	e.Required = required
+	e.Slots = make([]SlotExplanation, len(extents))
+	for i, extent := range extents {
+		e.Slots[i] = SlotExplanation{
+			Index:  i,
+			Extent: extent,
+			Seed:   sizes[i],
+			Size:   sizes[i],
+		}
+	}
+}
+
+func (e *Explanation) finish(sizes []int) {
+	if e == nil {
+		return
+	}
+	for i, size := range sizes {
+		e.Slots[i].Size = size
+	}
+}
@@ -0,0 +1,132 @@
+package engine
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/trippwill/keel/core"
+)
+
+func TestExplainExtentsSteps(t *testing.T) {
+	flexMax := func(units, max int) core.ExtentConstraint {
+		return core.ExtentConstraint{Kind: core.ExtentFlex, Units: units, MaxCells: max}
+	}
+	cases := []struct {
+		name    string
+		total   int
+		extents []core.ExtentConstraint
+		want    []SlotExplanation
+	}{
+		{
+			name:    "proportional share with remainder",
+			total:   10,
+			extents: []core.ExtentConstraint{flex(1), flex(3)},
+			want: []SlotExplanation{
+				{Index: 0, Extent: flex(1), Share: 2, Remainder: 1, Size: 3},
+				{Index: 1, Extent: flex(3), Share: 7, Size: 7},
+			},
+		},
+		{
+			name:    "caps then soft-max overflow",
+			total:   10,
+			extents: []core.ExtentConstraint{flexMax(1, 2), flexMax(1, 3)},
+			want: []SlotExplanation{
+				{Index: 0, Extent: flexMax(1, 2), Share: 2, Overflow: 3, Size: 5},
+				{Index: 1, Extent: flexMax(1, 3), Share: 2, Remainder: 1, Overflow: 2, Size: 5},
+			},
+		},
+		{
+			name:    "capped share is redistributed",
+			total:   9,
+			extents: []core.ExtentConstraint{flexMax(1, 2), flex(1), fixed(3)},
+			want: []SlotExplanation{
+				{Index: 0, Extent: flexMax(1, 2), Share: 2, Capped: 1, Size: 2},
+				{Index: 1, Extent: flex(1), Share: 3, Remainder: 1, Size: 4},
+				{Index: 2, Extent: fixed(3), Seed: 3, Size: 3},
+			},
+		},
+		{
+			name:    "leftover goes to last without flex",
+			total:   8,
+			extents: []core.ExtentConstraint{fixed(2), fixed(3)},
+			want: []SlotExplanation{
+				{Index: 0, Extent: fixed(2), Seed: 2, Size: 2},
+				{Index: 1, Extent: fixed(3), Seed: 3, Leftover: 3, Size: 6},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ExplainExtents(tc.total, tc.extents)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got.Slots, tc.want) {
+				t.Fatalf("expected %+v, got %+v", tc.want, got.Slots)
+			}
+			sizes, _, err := ArrangeExtents(tc.total, tc.extents)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for i, slot := range got.Slots {
+				sum := slot.Seed + slot.Share + slot.Remainder + slot.Overflow + slot.Leftover
+				if sum != slot.Size || slot.Size != sizes[i] {
+					t.Fatalf("slot %d: steps sum to %d, size %d, arranged %d", i, sum, slot.Size, sizes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExplainExtentsTooSmall(t *testing.T) {
+	got, err := ExplainExtents(2, []core.ExtentConstraint{fixed(2), {Kind: core.ExtentFlex, Units: 1, MinCells: 3}})
+	if !errors.Is(err, core.ErrExtentTooSmall) {
+		t.Fatalf("expected ErrExtentTooSmall, got %v", err)
+	}
+	if got.Required != 5 || got.Total != 2 {
+		t.Fatalf("expected required 5 of 2, got %d of %d", got.Required, got.Total)
+	}
+	if len(got.Slots) != 2 || got.Slots[1].Seed != 3 {
+		t.Fatalf("expected seeds, got %+v", got.Slots)
+	}
+}
+
+func TestExplainStackNilSlot(t *testing.T) {
+	stack := testStack{ExtentConstraint: flex(1), axis: core.AxisHorizontal, slots: []core.Spec{nil}}
+	_, err := ExplainStack(4, stack)
+	var slotErr *core.SlotError
+	if !errors.As(err, &slotErr) {
+		t.Fatalf("expected SlotError, got %v", err)
+	}
+}
+
+func TestExplanationString(t *testing.T) {
+	stack := testStack{
+		ExtentConstraint: flex(1),
+		axis:             core.AxisHorizontal,
+		slots: []core.Spec{
+			testFrame{ExtentConstraint: fixed(3), id: "a"},
+			testFrame{ExtentConstraint: flex(1), id: "b"},
+		},
+	}
+	got, err := ExplainStack(10, stack)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(got.String(), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %q", got.String())
+	}
+	if lines[0] != "total 10, required 3" {
+		t.Fatalf("unexpected summary: %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "slot  extent") {
+		t.Fatalf("unexpected header: %q", lines[1])
+	}
+	if fields := strings.Fields(lines[3]); fields[1] != "Flex(1)" || fields[len(fields)-1] != "7" {
+		t.Fatalf("unexpected row: %q", lines[3])
+	}
+}
@@ -1,9 +1,11 @@
 package engine
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"strconv"
+	"strings"
 
 	"github.com/trippwill/keel/core"
 	"github.com/trippwill/keel/logging"
@@ -32,78 +34,185 @@ type Layout[KID core.KeelID] struct {
 }
 
 // LayoutNode represents an arranged layout node.
+// Path is the slash-delimited slot path from the root (e.g. "/0/1").
 type LayoutNode[KID core.KeelID] struct {
 	Kind  NodeKind
 	Axis  core.Axis
 	Rect  Rect
+	Path  string
 	Frame core.FrameSpec[KID]
 	Slots []LayoutNode[KID]
 }
 
 // Arrange arranges a [core.Spec] tree into concrete allocations for the given size.
 func Arrange[KID core.KeelID](spec core.Spec, size core.Size, logger *slog.Logger) (Layout[KID], error) {
-	path := ""
-	if logger != nil {
-		path = "/"
+	return ArrangeContext[KID](context.Background(), spec, size, logger)
+}
+
+// ArrangeContext is [Arrange] with a context passed through to log handlers.
+func ArrangeContext[KID core.KeelID](ctx context.Context, spec core.Spec, size core.Size, logger *slog.Logger) (Layout[KID], error) {
+	a := Arranger[KID]{logger: logger}
+	return a.arrange(ctx, spec, size)
+}
+
+// Contains reports whether the point (x, y) lies inside the rect.
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// FrameAt returns the frame node whose rect contains the point (x, y).
+func (l Layout[KID]) FrameAt(x, y int) (LayoutNode[KID], bool) {
+	node := l.Root
+	for node.Rect.Contains(x, y) {
+		if node.Kind == NodeFrame {
+			return node, node.Frame != nil
+		}
+		next, ok := slotAt(node, x, y)
+		if !ok {
+			break
+		}
+		node = next
 	}
-	rect := Rect{X: 0, Y: 0, Width: size.Width, Height: size.Height}
-	root, err := arrangeWithPath[KID](spec, rect, path, logger)
-	if err != nil {
-		return Layout[KID]{}, err
+	return LayoutNode[KID]{}, false
+}
+
+// NodesAt returns the nodes whose rects contain the point (x, y), from the
+// root down to the deepest node. It is empty when the point is outside the
+// layout.
+func (l Layout[KID]) NodesAt(x, y int) []LayoutNode[KID] {
+	var nodes []LayoutNode[KID]
+	node := l.Root
+	for node.Rect.Contains(x, y) {
+		nodes = append(nodes, node)
+		next, ok := slotAt(node, x, y)
+		if !ok {
+			break
+		}
+		node = next
 	}
-	return Layout[KID]{
-		Width:  size.Width,
-		Height: size.Height,
-		Root:   root,
-	}, nil
+	return nodes
 }
 
-func arrangeWithPath[KID core.KeelID](spec core.Spec, rect Rect, path string, logger *slog.Logger) (LayoutNode[KID], error) {
+// Frame returns the first frame node with the given ID in document order.
+func (l Layout[KID]) Frame(id KID) (LayoutNode[KID], bool) {
+	return findFrame(l.Root, id)
+}
+
+// Node returns the node at a slash-delimited slot path such as "/0/1".
+// The root is at "/"; paths with empty segments, such as "/0//1" or "/0/",
+// are not found.
+func (l Layout[KID]) Node(path string) (LayoutNode[KID], bool) {
+	if !strings.HasPrefix(path, "/") {
+		return LayoutNode[KID]{}, false
+	}
+	node := l.Root
+	if path == "/" {
+		return node, true
+	}
+	for _, part := range strings.Split(path[1:], "/") {
+		index, err := strconv.Atoi(part)
+		if err != nil || index < 0 || index >= len(node.Slots) {
+			return LayoutNode[KID]{}, false
+		}
+		node = node.Slots[index]
+	}
+	return node, true
+}
+
+// Translate returns a copy of the node and its descendants with every rect
+// moved by (dx, dy).
+func (n LayoutNode[KID]) Translate(dx, dy int) LayoutNode[KID] {
+	n.Rect.X += dx
+	n.Rect.Y += dy
+	if len(n.Slots) > 0 {
+		slots := make([]LayoutNode[KID], len(n.Slots))
+		for i, slot := range n.Slots {
+			slots[i] = slot.Translate(dx, dy)
+		}
+		n.Slots = slots
+	}
+	return n
+}
+
+func slotAt[KID core.KeelID](node LayoutNode[KID], x, y int) (LayoutNode[KID], bool) {
+	for _, slot := range node.Slots {
+		if slot.Rect.Contains(x, y) {
+			return slot, true
+		}
+	}
+	return LayoutNode[KID]{}, false
+}
+
+func findFrame[KID core.KeelID](node LayoutNode[KID], id KID) (LayoutNode[KID], bool) {
+	if node.Kind == NodeFrame {
+		return node, node.Frame != nil && node.Frame.ID() == id
+	}
+	for _, slot := range node.Slots {
+		if found, ok := findFrame(slot, id); ok {
+			return found, true
+		}
+	}
+	return LayoutNode[KID]{}, false
+}
+
+func (a *Arranger[KID]) arrangeWithPath(ctx context.Context, spec core.Spec, rect Rect, path string) (LayoutNode[KID], error) {
+	logger := a.logger
 	switch n := spec.(type) {
 	case core.StackSpec:
-		return arrangeStackWithPath[KID](n, rect, path, logger)
+		return a.arrangeStackWithPath(ctx, n, rect, path)
 	case core.FrameSpec[KID]:
 		return LayoutNode[KID]{
 			Kind:  NodeFrame,
 			Rect:  rect,
+			Path:  path,
 			Frame: n,
 		}, nil
 	default:
-		err := &core.ConfigError{Reason: core.ErrUnknownSpec}
-		logError(logger, path, "dispatch", err)
+		err := &core.ConfigError{Reason: core.ErrUnknownSpec, Path: path}
+		logError(ctx, logger, path, "dispatch", err)
 		return LayoutNode[KID]{}, err
 	}
 }
 
-func arrangeStackWithPath[KID core.KeelID](stack core.StackSpec, rect Rect, path string, logger *slog.Logger) (LayoutNode[KID], error) {
+func (a *Arranger[KID]) arrangeStackWithPath(ctx context.Context, stack core.StackSpec, rect Rect, path string) (LayoutNode[KID], error) {
+	logger := a.logger
 	length := stack.Len()
 	if length <= 0 {
 		return LayoutNode[KID]{
 			Kind:  NodeStack,
 			Rect:  rect,
+			Path:  path,
 			Slots: nil,
 		}, nil
 	}
 
 	axis := stack.Axis()
 	if axis != core.AxisHorizontal && axis != core.AxisVertical {
-		err := &core.ConfigError{Reason: core.ErrInvalidAxis}
-		logError(logger, path, "stack.axis", err)
+		err := &core.ConfigError{Reason: core.ErrInvalidAxis, Path: path}
+		logError(ctx, logger, path, "stack.axis", err)
 		return LayoutNode[KID]{}, err
 	}
 
-	extents, err := GetStackExtents(stack)
+	extents, err := appendStackExtents(a.extents, stack)
 	if err != nil {
-		logError(logger, path, "stack.slot", err)
+		err = withPath(err, path)
+		logError(ctx, logger, path, "stack.slot", err)
 		return LayoutNode[KID]{}, err
 	}
 
+	a.extents = extents
+
 	total := rect.Width
 	if axis == core.AxisVertical {
 		total = rect.Height
 	}
 
-	sizes, required, err := ArrangeExtents(total, extents)
+	var ex *Explanation
+	if logging.Enabled(logger, logging.LevelTrace) {
+		ex = &Explanation{}
+	}
+
+	sizes, required, err := arrangeExtentsInto(a.sizeSlice(len(extents)), total, extents, ex, &a.scratch)
 	if err != nil {
 		if errors.Is(err, core.ErrExtentTooSmall) {
 			source := "horizontal split"
@@ -116,31 +225,38 @@ func arrangeStackWithPath[KID core.KeelID](stack core.StackSpec, rect Rect, path
 				Have:   total,
 				Source: source,
 				Reason: "allocation",
+				Path:   path,
 			}
+		} else {
+			err = withPath(err, path)
 		}
-		logError(logger, path, "stack.arrange", err)
+		logError(ctx, logger, path, "stack.arrange", err)
 		return LayoutNode[KID]{}, err
 	}
 
-	logging.LogEvent(
-		logger,
-		slog.LevelDebug,
-		logging.EventStackAlloc,
-		path,
-		slog.String("axis", axis.String()),
-		slog.Int("total", total),
-		slog.Int("slots", len(sizes)),
-		slog.Any("sizes", sizes),
-		slog.Int("required", required),
-	)
+	if logging.Enabled(logger, slog.LevelDebug) {
+		level := slog.LevelDebug
+		attrs := []slog.Attr{
+			slog.String("axis", axis.String()),
+			slog.Int("total", total),
+			slog.Int("slots", len(sizes)),
+			slog.Any("sizes", sizes),
+			slog.Int("required", required),
+		}
+		if ex != nil {
+			level = logging.LevelTrace
+			attrs = append(attrs, slog.String("explain", ex.String()))
+		}
+		logging.LogEventContext(ctx, logger, level, logging.EventStackAlloc, path, attrs...)
+	}
 
-	slots := make([]LayoutNode[KID], length)
+	slots := a.nodeSlice(length)
 	offset := 0
 	for i, size := range sizes {
 		slot, ok := stack.Slot(i)
 		if !ok || slot == nil {
-			err := &core.SlotError{Index: i, Reason: core.ErrNilSlot}
-			logError(logger, path, "stack.slot", err)
+			err := &core.SlotError{Index: i, Reason: core.ErrNilSlot, Path: path}
+			logError(ctx, logger, path, "stack.slot", err)
 			return LayoutNode[KID]{}, err
 		}
 
@@ -153,14 +269,9 @@ func arrangeStackWithPath[KID core.KeelID](stack core.StackSpec, rect Rect, path
 			slotRect.Height = size
 		}
 
-		slotPath := path
-		if logger != nil {
-			slotPath = appendPath(path, i)
-		}
-
-		slotNode, err := arrangeWithPath[KID](slot, slotRect, slotPath, logger)
+		slotNode, err := a.arrangeWithPath(ctx, slot, slotRect, a.path(path, i))
 		if err != nil {
-			logError(logger, path, "stack.render", err)
+			logError(ctx, logger, path, "stack.render", err)
 			return LayoutNode[KID]{}, err
 		}
 
@@ -172,12 +283,30 @@ func arrangeStackWithPath[KID core.KeelID](stack core.StackSpec, rect Rect, path
 		Kind:  NodeStack,
 		Axis:  axis,
 		Rect:  rect,
+		Path:  path,
 		Slots: slots,
 	}, nil
 }
 
-func logError(logger *slog.Logger, path string, stage string, err error) {
-	logging.LogError(logger, path, stage, err)
+func logError(ctx context.Context, logger *slog.Logger, path string, stage string, err error) {
+	logging.LogErrorContext(ctx, logger, path, stage, err)
+}
+
+// withPath records the stack path on core errors that lack one.
+func withPath(err error, path string) error {
+	var slotErr *core.SlotError
+	if errors.As(err, &slotErr) && slotErr.Path == "" {
+		slotErr.Path = path
+	}
+	var extentErr *core.ExtentError
+	if errors.As(err, &extentErr) && extentErr.Path == "" {
+		extentErr.Path = path
+	}
+	var configErr *core.ConfigError
+	if errors.As(err, &configErr) && configErr.Path == "" {
+		configErr.Path = path
+	}
+	return err
 }
 
 func appendPath(path string, index int) string {
@@ -156,3 +156,159 @@ func TestArrangeTooSmallReturnsContext(t *testing.T) {
 		t.Fatalf("expected reason %q, got %q", "allocation", tooSmall.Reason)
 	}
 }
+
+func TestArrangeAssignsPaths(t *testing.T) {
+	layout := testStack{
+		ExtentConstraint: flex(1),
+		axis:             core.AxisHorizontal,
+		slots: []core.Spec{
+			testFrame{ExtentConstraint: fixed(3), id: "a"},
+			testStack{
+				ExtentConstraint: flex(1),
+				axis:             core.AxisVertical,
+				slots: []core.Spec{
+					testFrame{ExtentConstraint: fixed(2), id: "b"},
+					testFrame{ExtentConstraint: flex(1), id: "c"},
+				},
+			},
+		},
+	}
+	arranged, err := Arrange[string](layout, core.Size{Width: 10, Height: 5}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if arranged.Root.Path != "/" {
+		t.Fatalf("expected root path %q, got %q", "/", arranged.Root.Path)
+	}
+	if got := arranged.Root.Slots[0].Path; got != "/0" {
+		t.Fatalf("expected path %q, got %q", "/0", got)
+	}
+	if got := arranged.Root.Slots[1].Slots[1].Path; got != "/1/1" {
+		t.Fatalf("expected path %q, got %q", "/1/1", got)
+	}
+}
+
+func TestArrangeNestedErrorsCarryPath(t *testing.T) {
+	nested := func(slot core.Spec) core.Spec {
+		return testStack{
+			ExtentConstraint: flex(1),
+			axis:             core.AxisHorizontal,
+			slots: []core.Spec{
+				testFrame{ExtentConstraint: fixed(1), id: "a"},
+				testStack{ExtentConstraint: flex(1), axis: core.AxisVertical, slots: []core.Spec{slot}},
+			},
+		}
+	}
+
+	_, err := Arrange[string](nested(nil), core.Size{Width: 4, Height: 4}, nil)
+	var slotErr *core.SlotError
+	if !errors.As(err, &slotErr) {
+		t.Fatalf("expected SlotError, got %v", err)
+	}
+	if slotErr.Path != "/1" || slotErr.Index != 0 {
+		t.Fatalf("expected slot 0 at /1, got %+v", slotErr)
+	}
+
+	_, err = Arrange[string](nested(testFrame{ExtentConstraint: core.ExtentConstraint{Kind: core.ExtentFlex}, id: "b"}), core.Size{Width: 4, Height: 4}, nil)
+	var extentErr *core.ExtentError
+	if !errors.As(err, &extentErr) {
+		t.Fatalf("expected ExtentError, got %v", err)
+	}
+	if extentErr.Path != "/1" {
+		t.Fatalf("expected path /1, got %q", extentErr.Path)
+	}
+
+	_, err = Arrange[string](nested(testFrame{ExtentConstraint: fixed(9), id: "b"}), core.Size{Width: 4, Height: 4}, nil)
+	var tooSmall *core.ExtentTooSmallError
+	if !errors.As(err, &tooSmall) {
+		t.Fatalf("expected ExtentTooSmallError, got %v", err)
+	}
+	if tooSmall.Path != "/1" {
+		t.Fatalf("expected path /1, got %q", tooSmall.Path)
+	}
+}
+
+func TestLayoutFrameLookup(t *testing.T) {
+	layout := testStack{
+		ExtentConstraint: flex(1),
+		axis:             core.AxisHorizontal,
+		slots: []core.Spec{
+			testFrame{ExtentConstraint: fixed(3), id: "a"},
+			testStack{
+				ExtentConstraint: flex(1),
+				axis:             core.AxisVertical,
+				slots: []core.Spec{
+					testFrame{ExtentConstraint: fixed(2), id: "b"},
+					testFrame{ExtentConstraint: flex(1), id: "c"},
+				},
+			},
+		},
+	}
+	arranged, err := Arrange[string](layout, core.Size{Width: 10, Height: 5}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		x, y int
+		want string
+	}{
+		{0, 0, "a"},
+		{2, 4, "a"},
+		{3, 0, "b"},
+		{9, 1, "b"},
+		{3, 2, "c"},
+		{9, 4, "c"},
+	}
+	for _, tc := range cases {
+		node, ok := arranged.FrameAt(tc.x, tc.y)
+		if !ok || node.Frame.ID() != tc.want {
+			t.Fatalf("expected frame %q at %d,%d, got %+v", tc.want, tc.x, tc.y, node)
+		}
+	}
+	if _, ok := arranged.FrameAt(10, 0); ok {
+		t.Fatalf("expected no frame outside the layout")
+	}
+	if _, ok := arranged.FrameAt(-1, 0); ok {
+		t.Fatalf("expected no frame outside the layout")
+	}
+
+	nodes := arranged.NodesAt(4, 3)
+	if len(nodes) != 3 || nodes[0].Path != "/" || nodes[1].Path != "/1" || nodes[2].Path != "/1/1" {
+		t.Fatalf("expected nodes /, /1, /1/1, got %+v", nodes)
+	}
+	if nodes := arranged.NodesAt(0, 5); len(nodes) != 0 {
+		t.Fatalf("expected no nodes outside the layout, got %+v", nodes)
+	}
+
+	node, ok := arranged.Frame("c")
+	if !ok || node.Path != "/1/1" {
+		t.Fatalf("expected frame c at /1/1, got %+v", node)
+	}
+	want := Rect{X: 3, Y: 2, Width: 7, Height: 3}
+	if node.Rect != want {
+		t.Fatalf("expected rect %+v, got %+v", want, node.Rect)
+	}
+	if _, ok := arranged.Frame("missing"); ok {
+		t.Fatalf("expected missing frame lookup to fail")
+	}
+
+	for _, path := range []string{"/", "/1", "/1/1"} {
+		if node, ok := arranged.Node(path); !ok || node.Path != path {
+			t.Fatalf("expected node at %s, got %+v", path, node)
+		}
+	}
+	for _, path := range []string{"", "1", "/2", "/0/0", "/x", "//", "/1/", "/1//1", "//1"} {
+		if _, ok := arranged.Node(path); ok {
+			t.Fatalf("expected no node at %q", path)
+		}
+	}
+
+	moved := arranged.Root.Slots[1].Translate(-3, 0)
+	if moved.Rect != (Rect{Width: 7, Height: 5}) || moved.Slots[1].Rect != (Rect{Y: 2, Width: 7, Height: 3}) {
+		t.Fatalf("unexpected translated rects %+v", moved)
+	}
+	if arranged.Root.Slots[1].Slots[1].Rect != want {
+		t.Fatalf("expected translate to leave the layout unchanged")
+	}
+}
@@ -0,0 +1,117 @@
+package engine
+
+import "github.com/trippwill/keel/core"
+
+// SplitBuilder is a mutable [SplitSpec]. Every mutation bumps its
+// generation (see [core.Versioned]), so renderers holding a tree that
+// contains the builder re-arrange without an explicit invalidate.
+//
+// The generation is the builder's own counter plus the generations of its
+// slots, and never decreases: removing or replacing a slot folds the slot's
+// generation into the counter. A SplitBuilder is not safe for concurrent use,
+// and must not be mutated during a render.
+type SplitBuilder struct {
+	extent     core.ExtentConstraint
+	axis       core.Axis
+	rs         []core.Spec
+	generation uint64
+}
+
+// NewSplitBuilder creates a mutable split with the given axis, extent and
+// slots. The slots are copied. Panics on invalid axis.
+func NewSplitBuilder(axis core.Axis, extent core.ExtentConstraint, slots ...core.Spec) *SplitBuilder {
+	if (axis != core.AxisHorizontal) && (axis != core.AxisVertical) {
+		panic(core.ErrInvalidAxis)
+	}
+
+	return &SplitBuilder{
+		extent: extent,
+		axis:   axis,
+		rs:     append([]core.Spec(nil), slots...),
+	}
+}
+
+// Extent implements [core.Spec].
+func (b *SplitBuilder) Extent() core.ExtentConstraint { return b.extent }
+
+// Axis implements [core.StackSpec].
+func (b *SplitBuilder) Axis() core.Axis { return b.axis }
+
+// Len implements [core.StackSpec].
+func (b *SplitBuilder) Len() int { return len(b.rs) }
+
+// Slot implements [core.StackSpec].
+func (b *SplitBuilder) Slot(index int) (core.Spec, bool) {
+	if index < 0 || index >= len(b.rs) {
+		return nil, false
+	}
+
+	return b.rs[index], true
+}
+
+// Generation implements [core.Versioned].
+func (b *SplitBuilder) Generation() uint64 {
+	generation := b.generation
+	for _, slot := range b.rs {
+		generation += core.Generation(slot)
+	}
+	return generation
+}
+
+// Insert inserts slot before index; index Len appends.
+func (b *SplitBuilder) Insert(index int, slot core.Spec) error {
+	if index < 0 || index > len(b.rs) {
+		return &core.SlotError{Index: index, Reason: core.ErrSlotIndex}
+	}
+	if slot == nil {
+		return &core.SlotError{Index: index, Reason: core.ErrNilSlot}
+	}
+	b.rs = append(b.rs, nil)
+	copy(b.rs[index+1:], b.rs[index:])
+	b.rs[index] = slot
+	b.generation++
+	return nil
+}
+
+// Remove removes and returns the slot at index.
+func (b *SplitBuilder) Remove(index int) (core.Spec, error) {
+	if index < 0 || index >= len(b.rs) {
+		return nil, &core.SlotError{Index: index, Reason: core.ErrSlotIndex}
+	}
+	slot := b.rs[index]
+	copy(b.rs[index:], b.rs[index+1:])
+	b.rs[len(b.rs)-1] = nil
+	b.rs = b.rs[:len(b.rs)-1]
+	b.generation += core.Generation(slot) + 1
+	return slot, nil
+}
+
+// Replace replaces the slot at index and returns the previous slot.
+func (b *SplitBuilder) Replace(index int, slot core.Spec) (core.Spec, error) {
+	if index < 0 || index >= len(b.rs) {
+		return nil, &core.SlotError{Index: index, Reason: core.ErrSlotIndex}
+	}
+	if slot == nil {
+		return nil, &core.SlotError{Index: index, Reason: core.ErrNilSlot}
+	}
+	previous := b.rs[index]
+	b.rs[index] = slot
+	b.generation += core.Generation(previous) + 1
+	return previous, nil
+}
+
+// SetExtent replaces the extent of the split itself along its parent's axis.
+func (b *SplitBuilder) SetExtent(extent core.ExtentConstraint) {
+	b.extent = extent
+	b.generation++
+}
+
+// Spec returns an immutable [SplitSpec] with the current slots.
+func (b *SplitBuilder) Spec() SplitSpec {
+	return NewSplitSpec(b.axis, b.extent, append([]core.Spec(nil), b.rs...)...)
+}
+
+var (
+	_ core.StackSpec = (*SplitBuilder)(nil)
+	_ core.Versioned = (*SplitBuilder)(nil)
+)
@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/trippwill/keel/core"
+)
+
+func TestSplitBuilderMutations(t *testing.T) {
+	flex := core.ExtentConstraint{Kind: core.ExtentFlex, Units: 1}
+	a := NewPanelSpec(flex, core.FitExact, "a")
+	b := NewPanelSpec(flex, core.FitExact, "b")
+	c := NewPanelSpec(flex, core.FitExact, "c")
+	builder := NewSplitBuilder(core.AxisHorizontal, flex, a)
+
+	generation := builder.Generation()
+	bumped := func(step string) {
+		t.Helper()
+		next := builder.Generation()
+		if next <= generation {
+			t.Fatalf("%s: expected generation above %d, got %d", step, generation, next)
+		}
+		generation = next
+	}
+
+	if err := builder.Insert(1, c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bumped("insert")
+	if err := builder.Insert(1, b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bumped("insert middle")
+	for i, want := range []string{"a", "b", "c"} {
+		slot, ok := builder.Slot(i)
+		if !ok || slot.(PanelSpec[string]).ID() != want {
+			t.Fatalf("slot %d: expected %s, got %v", i, want, slot)
+		}
+	}
+
+	removed, err := builder.Remove(0)
+	if err != nil || removed != a {
+		t.Fatalf("expected to remove a, got %v, %v", removed, err)
+	}
+	bumped("remove")
+	previous, err := builder.Replace(1, a)
+	if err != nil || previous != c {
+		t.Fatalf("expected to replace c, got %v, %v", previous, err)
+	}
+	bumped("replace")
+	builder.SetExtent(core.ExtentConstraint{Kind: core.ExtentFixed, Units: 4})
+	bumped("set extent")
+	if got := builder.Extent().Kind; got != core.ExtentFixed {
+		t.Fatalf("expected fixed extent, got %v", got)
+	}
+	if got := builder.Len(); got != 2 {
+		t.Fatalf("expected 2 slots, got %d", got)
+	}
+
+	snapshot := builder.Spec()
+	if _, err := builder.Remove(0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot.Len() != 2 {
+		t.Fatalf("expected snapshot to keep 2 slots, got %d", snapshot.Len())
+	}
+}
+
+func TestSplitBuilderErrors(t *testing.T) {
+	flex := core.ExtentConstraint{Kind: core.ExtentFlex, Units: 1}
+	builder := NewSplitBuilder(core.AxisVertical, flex)
+	generation := builder.Generation()
+
+	var slotErr *core.SlotError
+	if err := builder.Insert(1, NewPanelSpec(flex, core.FitExact, "a")); !errors.As(err, &slotErr) || !errors.Is(slotErr.Reason, core.ErrSlotIndex) {
+		t.Fatalf("expected slot index error, got %v", err)
+	}
+	if err := builder.Insert(0, nil); !errors.As(err, &slotErr) || !errors.Is(slotErr.Reason, core.ErrNilSlot) {
+		t.Fatalf("expected nil slot error, got %v", err)
+	}
+	if _, err := builder.Remove(0); !errors.As(err, &slotErr) || slotErr.Index != 0 {
+		t.Fatalf("expected slot error, got %v", err)
+	}
+	if _, err := builder.Replace(-1, NewPanelSpec(flex, core.FitExact, "a")); !errors.As(err, &slotErr) {
+		t.Fatalf("expected slot error, got %v", err)
+	}
+	if got := builder.Generation(); got != generation {
+		t.Fatalf("expected failed edits to keep generation %d, got %d", generation, got)
+	}
+}
+
+func TestSplitBuilderGenerationBubbles(t *testing.T) {
+	flex := core.ExtentConstraint{Kind: core.ExtentFlex, Units: 1}
+	inner := NewSplitBuilder(core.AxisVertical, flex, NewPanelSpec(flex, core.FitExact, "a"))
+	outer := NewSplitBuilder(core.AxisHorizontal, flex, inner)
+	root := NewSplitSpec(core.AxisHorizontal, flex, outer)
+
+	before := core.Generation(root)
+	if err := inner.Insert(0, NewPanelSpec(flex, core.FitExact, "b")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	after := core.Generation(root)
+	if after <= before {
+		t.Fatalf("expected nested edit to bump root generation, got %d then %d", before, after)
+	}
+
+	// Removing the edited child must not lower the generation.
+	if _, err := outer.Remove(0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := core.Generation(root); got <= after {
+		t.Fatalf("expected generation above %d after removal, got %d", after, got)
+	}
+	if got := core.Generation(NewPanelSpec(flex, core.FitExact, "a")); got != 0 {
+		t.Fatalf("expected frames to have generation 0, got %d", got)
+	}
+}
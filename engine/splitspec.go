@@ -40,6 +40,16 @@ func (s SplitSpec) Axis() core.Axis { return s.axis }
 // Len implements [StackSpec].
 func (s SplitSpec) Len() int { return len(s.rs) }
 
+// Generation implements [core.Versioned] as the sum of the slot generations,
+// so changes to mutable slots such as a [SplitBuilder] bubble up.
+func (s SplitSpec) Generation() uint64 {
+	var generation uint64
+	for _, slot := range s.rs {
+		generation += core.Generation(slot)
+	}
+	return generation
+}
+
 // Slot implements [StackSpec].
 func (s SplitSpec) Slot(index int) (core.Spec, bool) {
 	if index < 0 || index >= len(s.rs) {
@@ -15,6 +15,21 @@ var (
 	ErrContentProviderMissing = errors.New("content provider missing")
 	// ErrUnknownFrameID indicates a content/style request for an unknown ID.
 	ErrUnknownFrameID = errors.New("unknown frame id")
+	// ErrDimensionMismatch indicates rendered output that differs from its allocation.
+	ErrDimensionMismatch = errors.New("dimension mismatch")
+	// ErrRenderCanceled indicates a render stopped because its context was done.
+	ErrRenderCanceled = errors.New("render canceled")
+	// ErrProviderPanic indicates a recovered panic in a content or style provider.
+	ErrProviderPanic = errors.New("provider panic")
+	// ErrLayoutMissing indicates a request that needs a cached layout before
+	// the first render.
+	ErrLayoutMissing = errors.New("layout missing")
+	// ErrUnknownPath indicates a request for a layout path with no node.
+	ErrUnknownPath = errors.New("unknown layout path")
+	// ErrRegistryIncomplete indicates a registry that does not match its spec.
+	ErrRegistryIncomplete = errors.New("registry incomplete")
+	// ErrMoveIntoSelf indicates a slot moved into its own subtree.
+	ErrMoveIntoSelf = errors.New("slot moved into itself")
 )
 
 // ContentProviderMissingError indicates a missing content provider for a frame ID.
@@ -45,13 +60,148 @@ func (e *UnknownFrameIDError) Unwrap() error {
 	return ErrUnknownFrameID
 }
 
+// RegistryError reports frame IDs in a spec without a registered content
+// provider (Missing) and registered IDs that no frame uses (Unused).
+// It wraps ErrRegistryIncomplete for errors.Is checks.
+type RegistryError struct {
+	Missing []any
+	Unused  []any
+}
+
+func (e *RegistryError) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing "+joinIDs(e.Missing))
+	}
+	if len(e.Unused) > 0 {
+		parts = append(parts, "unused "+joinIDs(e.Unused))
+	}
+	if len(parts) == 0 {
+		return ErrRegistryIncomplete.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrRegistryIncomplete, strings.Join(parts, "; "))
+}
+
+func (e *RegistryError) Unwrap() error {
+	return ErrRegistryIncomplete
+}
+
+func joinIDs(ids []any) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprint(id)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// UnknownPathError indicates a request for a layout path with no node.
+// It wraps ErrUnknownPath for errors.Is checks.
+type UnknownPathError struct {
+	Path string
+}
+
+func (e *UnknownPathError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrUnknownPath, e.Path)
+}
+
+func (e *UnknownPathError) Unwrap() error {
+	return ErrUnknownPath
+}
+
+// DimensionMismatchError indicates a frame or whole render whose output is
+// not exactly its allocated size. Got reports the line count and the width of
+// the first line that is not exactly Want.Width cells wide, or Want.Width when
+// only the line count differs. It wraps ErrDimensionMismatch for errors.Is
+// checks.
+type DimensionMismatchError struct {
+	Path      string
+	ID        any
+	Want, Got Size
+}
+
+func (e *DimensionMismatchError) Error() string {
+	at := location(e.Path, e.ID)
+	return fmt.Sprintf(
+		"%s%s: want %dx%d, got %dx%d",
+		ErrDimensionMismatch,
+		at,
+		e.Want.Width,
+		e.Want.Height,
+		e.Got.Width,
+		e.Got.Height,
+	)
+}
+
+func (e *DimensionMismatchError) Unwrap() error {
+	return ErrDimensionMismatch
+}
+
+// CanceledError indicates a render stopped because its context was done.
+// Path and ID locate the frame that was about to render or whose provider
+// failed after cancellation; Err is the context cause or provider error.
+// It wraps both ErrRenderCanceled and Err for errors.Is checks, so
+// errors.Is(err, context.DeadlineExceeded) works as expected.
+type CanceledError struct {
+	Path string
+	ID   any
+	Err  error
+}
+
+func (e *CanceledError) Error() string {
+	at := location(e.Path, e.ID)
+	if e.Err == nil {
+		return ErrRenderCanceled.Error() + at
+	}
+	return fmt.Sprintf("%s%s: %v", ErrRenderCanceled, at, e.Err)
+}
+
+func (e *CanceledError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{ErrRenderCanceled}
+	}
+	return []error{ErrRenderCanceled, e.Err}
+}
+
+// ProviderPanicError reports a panic recovered from a content or style
+// provider (see [Config.SetRecoverPanics]). Provider is "content" or "style",
+// Value is the value passed to panic and Stack is the goroutine stack at the
+// point of recovery. It wraps ErrProviderPanic, and Value when it is an
+// error, for errors.Is checks.
+type ProviderPanicError struct {
+	ID       any
+	Provider string
+	Value    any
+	Stack    []byte
+}
+
+func (e *ProviderPanicError) Error() string {
+	provider := e.Provider
+	if provider == "" {
+		provider = "provider"
+	} else {
+		provider += " provider"
+	}
+	return fmt.Sprintf("%s panicked for frame %v: %v", provider, e.ID, e.Value)
+}
+
+func (e *ProviderPanicError) Unwrap() []error {
+	if err, ok := e.Value.(error); ok {
+		return []error{ErrProviderPanic, err}
+	}
+	return []error{ErrProviderPanic}
+}
+
 // ExtentTooSmallError includes context about which allocation failed.
 // It wraps ErrExtentTooSmall for errors.Is checks.
+// Path is the slash-delimited layout path of the failing node (e.g. "/0/1")
+// and ID is the frame ID when the failure is attributable to a frame.
 type ExtentTooSmallError struct {
 	Axis       string
 	Need, Have int
 	Source     string
 	Reason     string
+	Path       string
+	ID         any
 }
 
 func (e *ExtentTooSmallError) Error() string {
@@ -63,15 +213,17 @@ func (e *ExtentTooSmallError) Error() string {
 	if e.Reason != "" {
 		reason = " (" + e.Reason + ")"
 	}
+	at := location(e.Path, nil)
 	axis := e.Axis
 	if axis == "" {
 		axis = "unknown"
 	}
 	return fmt.Sprintf(
-		"extent too small on %s axis%s%s: need %d, have %d",
+		"extent too small on %s axis%s%s%s: need %d, have %d",
 		axis,
 		source,
 		reason,
+		at,
 		e.Need,
 		e.Have,
 	)
@@ -83,10 +235,14 @@ func (e *ExtentTooSmallError) Unwrap() error {
 
 // SpecError describes a configuration issue with a spec, slot, or extent.
 // It wraps ErrConfigurationInvalid for errors.Is checks.
+// Path is the slash-delimited layout path of the failing node; for slot and
+// extent errors it names the owning stack. ID is the frame ID when known.
 type SpecError struct {
 	Kind   string
 	Index  int
 	Reason string
+	Path   string
+	ID     any
 }
 
 const (
@@ -104,12 +260,13 @@ const (
 
 func (e *SpecError) Error() string {
 	parts := make([]string, 0, 2)
-	if e.Kind != "" {
-		if e.Index >= 0 {
-			parts = append(parts, fmt.Sprintf("%s %d", e.Kind, e.Index))
-		} else {
-			parts = append(parts, e.Kind)
-		}
+	subject := e.Kind
+	if e.Kind != "" && e.Index >= 0 {
+		subject = fmt.Sprintf("%s %d", e.Kind, e.Index)
+	}
+	subject = strings.TrimSpace(subject + location(e.Path, e.ID))
+	if subject != "" {
+		parts = append(parts, subject)
 	}
 	if e.Reason != "" {
 		parts = append(parts, e.Reason)
@@ -123,3 +280,16 @@ func (e *SpecError) Error() string {
 func (e *SpecError) Unwrap() error {
 	return ErrConfigurationInvalid
 }
+
+// location formats an optional " at <path> (frame <id>)" suffix, matching the
+// errors from package core.
+func location(path string, id any) string {
+	loc := ""
+	if path != "" {
+		loc = " at " + path
+	}
+	if id != nil {
+		loc += fmt.Sprintf(" (frame %v)", id)
+	}
+	return loc
+}
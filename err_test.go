@@ -1,6 +1,7 @@
 package keel
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"testing"
@@ -23,6 +24,22 @@ func TestExtentTooSmallErrorFormatting(t *testing.T) {
 	}
 }
 
+func TestExtentTooSmallErrorFormattingWithPath(t *testing.T) {
+	err := &ExtentTooSmallError{
+		Axis:   "Vertical",
+		Need:   3,
+		Have:   1,
+		Source: "frame b",
+		Reason: "frame",
+		Path:   "/1/0",
+		ID:     "b",
+	}
+	want := "extent too small on Vertical axis for frame b (frame) at /1/0: need 3, have 1"
+	if err.Error() != want {
+		t.Fatalf("expected %q, got %q", want, err.Error())
+	}
+}
+
 func TestExtentTooSmallErrorUnknownAxis(t *testing.T) {
 	err := &ExtentTooSmallError{Need: 1, Have: 0}
 	if err.Error() == "" || !strings.HasPrefix(err.Error(), "extent too small on") {
@@ -40,6 +57,9 @@ func TestSpecErrorFormatting(t *testing.T) {
 		{"kind only", &SpecError{Kind: SpecKindAxis, Index: -1}, "configuration invalid: axis"},
 		{"reason only", &SpecError{Reason: "bad"}, "configuration invalid: bad"},
 		{"empty", &SpecError{}, "configuration invalid"},
+		{"path", &SpecError{Kind: SpecKindSlot, Index: 2, Reason: "nil slot", Path: "/0/1"}, "configuration invalid: slot 2 at /0/1: nil slot"},
+		{"path and id", &SpecError{Kind: SpecKindConfig, Index: -1, Path: "/1", ID: "a"}, "configuration invalid: config at /1 (frame a)"},
+		{"path only", &SpecError{Index: -1, Path: "/1", Reason: "bad"}, "configuration invalid: at /1: bad"},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -66,3 +86,60 @@ func TestUnknownFrameIDError(t *testing.T) {
 		t.Fatalf("expected ErrUnknownFrameID")
 	}
 }
+
+func TestCanceledError(t *testing.T) {
+	err := &CanceledError{Path: "/1", ID: "b", Err: context.DeadlineExceeded}
+	want := "render canceled at /1 (frame b): context deadline exceeded"
+	if err.Error() != want {
+		t.Fatalf("expected %q, got %q", want, err.Error())
+	}
+	if !errors.Is(err, ErrRenderCanceled) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected ErrRenderCanceled and context.DeadlineExceeded")
+	}
+	if got := (&CanceledError{}).Error(); got != "render canceled" {
+		t.Fatalf("expected bare message, got %q", got)
+	}
+}
+
+func TestProviderPanicError(t *testing.T) {
+	err := &ProviderPanicError{ID: "a", Provider: "style", Value: "boom"}
+	want := "style provider panicked for frame a: boom"
+	if err.Error() != want {
+		t.Fatalf("expected %q, got %q", want, err.Error())
+	}
+	if !errors.Is(err, ErrProviderPanic) {
+		t.Fatalf("expected ErrProviderPanic")
+	}
+}
+
+func TestRegistryError(t *testing.T) {
+	err := &RegistryError{Missing: []any{"a", "b"}, Unused: []any{"z"}}
+	want := "registry incomplete: missing a, b; unused z"
+	if err.Error() != want {
+		t.Fatalf("expected %q, got %q", want, err.Error())
+	}
+	if !errors.Is(err, ErrRegistryIncomplete) {
+		t.Fatalf("expected ErrRegistryIncomplete")
+	}
+}
+
+func TestUnknownPathError(t *testing.T) {
+	err := &UnknownPathError{Path: "/3"}
+	if err.Error() != "unknown layout path: /3" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+	if !errors.Is(err, ErrUnknownPath) {
+		t.Fatalf("expected ErrUnknownPath")
+	}
+}
+
+func TestDimensionMismatchError(t *testing.T) {
+	err := &DimensionMismatchError{Path: "/1", ID: "b", Want: Size{Width: 2, Height: 1}, Got: Size{Width: 3, Height: 1}}
+	want := "dimension mismatch at /1 (frame b): want 2x1, got 3x1"
+	if err.Error() != want {
+		t.Fatalf("expected %q, got %q", want, err.Error())
+	}
+	if !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("expected ErrDimensionMismatch")
+	}
+}
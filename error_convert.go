@@ -26,19 +26,21 @@ func convertError(err error) error {
 			Have:   coreTooSmall.Have,
 			Source: coreTooSmall.Source,
 			Reason: coreTooSmall.Reason,
+			Path:   coreTooSmall.Path,
+			ID:     coreTooSmall.ID,
 		}
 	}
 	var slotErr *core.SlotError
 	if errors.As(err, &slotErr) {
-		return newSpecError(SpecKindSlot, slotErr.Index, slotErr.Reason)
+		return locate(newSpecError(SpecKindSlot, slotErr.Index, slotErr.Reason), slotErr.Path, nil)
 	}
 	var extentErr *core.ExtentError
 	if errors.As(err, &extentErr) {
-		return newSpecError(SpecKindExtent, extentErr.Index, extentErr.Reason)
+		return locate(newSpecError(SpecKindExtent, extentErr.Index, extentErr.Reason), extentErr.Path, nil)
 	}
 	var configErr *core.ConfigError
 	if errors.As(err, &configErr) {
-		return newSpecError(kindForReason(configErr.Reason), -1, configErr.Reason)
+		return locate(newSpecError(kindForReason(configErr.Reason), -1, configErr.Reason), configErr.Path, configErr.ID)
 	}
 
 	switch {
@@ -77,6 +79,12 @@ func newSpecError(kind string, index int, reason error) *SpecError {
 	return spec
 }
 
+func locate(spec *SpecError, path string, id any) *SpecError {
+	spec.Path = path
+	spec.ID = id
+	return spec
+}
+
 func kindForReason(reason error) string {
 	if reason == nil {
 		return SpecKindConfig
@@ -86,7 +94,7 @@ func kindForReason(reason error) string {
 		return SpecKindAxis
 	case errors.Is(reason, core.ErrUnknownSpec):
 		return SpecKindSpec
-	case errors.Is(reason, core.ErrNilSlot):
+	case errors.Is(reason, core.ErrNilSlot), errors.Is(reason, core.ErrSlotIndex):
 		return SpecKindSlot
 	case errors.Is(reason, core.ErrInvalidTotal),
 		errors.Is(reason, core.ErrInvalidExtentKind),
@@ -57,6 +57,34 @@ func TestConvertErrorCoreSlotError(t *testing.T) {
 	}
 }
 
+func TestConvertErrorCarriesLocation(t *testing.T) {
+	out := convertError(&core.SlotError{Index: 2, Reason: core.ErrNilSlot, Path: "/0/1"})
+	specErr, ok := out.(*SpecError)
+	if !ok {
+		t.Fatalf("expected SpecError, got %T", out)
+	}
+	if specErr.Path != "/0/1" {
+		t.Fatalf("expected path %q, got %q", "/0/1", specErr.Path)
+	}
+
+	out = convertError(&core.ConfigError{Path: "/1", ID: "a"})
+	if !errors.As(out, &specErr) {
+		t.Fatalf("expected SpecError, got %T", out)
+	}
+	if specErr.Path != "/1" || specErr.ID != "a" {
+		t.Fatalf("unexpected location: %+v", specErr)
+	}
+
+	out = convertError(&core.ExtentTooSmallError{Axis: core.AxisHorizontal, Path: "/2", ID: "b"})
+	var tooSmall *ExtentTooSmallError
+	if !errors.As(out, &tooSmall) {
+		t.Fatalf("expected ExtentTooSmallError, got %T", out)
+	}
+	if tooSmall.Path != "/2" || tooSmall.ID != "b" {
+		t.Fatalf("unexpected location: %+v", tooSmall)
+	}
+}
+
 func TestConvertErrorCoreExtentError(t *testing.T) {
 	coreErr := &core.ExtentError{Index: 1, Reason: core.ErrInvalidExtentUnits}
 	out := convertError(coreErr)
@@ -95,3 +95,33 @@ func ExampleSplitStyleProvider(id string) *gloss.Style {
 		return nil
 	}
 }
+
+// ExampleSplitRegistry returns a registry with a content and style provider
+// per frame of the example layout.
+func ExampleSplitRegistry() *keel.Registry[string] {
+	return keel.NewRegistry[string]().
+		Content("header", func(string, keel.FrameInfo) (string, error) {
+			return "Chiplog Dashboard", nil
+		}).
+		Style("header", func(string) *gloss.Style { return &headerStyle }).
+		Content("nav", func(string, keel.FrameInfo) (string, error) {
+			return "Queues\n- ingest\n- parse\n- render\n- ship", nil
+		}).
+		Style("nav", func(string) *gloss.Style { return &navStyle }).
+		Content("feed", func(string, keel.FrameInfo) (string, error) {
+			return "Latest\n- build ok\n- cache warm\n- alloc pass", nil
+		}).
+		Style("feed", func(string) *gloss.Style { return &feedStyle }).
+		Content("detail", func(string, keel.FrameInfo) (string, error) {
+			return "Detail\nid: 42\nstatus: running", nil
+		}).
+		Style("detail", func(string) *gloss.Style { return &detailStyle }).
+		Content("status", func(string, keel.FrameInfo) (string, error) {
+			return "status: connected", nil
+		}).
+		Style("status", func(string) *gloss.Style { return &statusStyle }).
+		Content("help", func(string, keel.FrameInfo) (string, error) {
+			return "?: help  q: quit", nil
+		}).
+		Style("help", func(string) *gloss.Style { return &helpStyle })
+}
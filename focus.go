@@ -0,0 +1,282 @@
+package keel
+
+import (
+	"github.com/trippwill/keel/core"
+	"github.com/trippwill/keel/engine"
+)
+
+// Direction selects a neighbor for [Focus.Move].
+type Direction uint8
+
+const (
+	// DirectionLeft moves toward smaller X.
+	DirectionLeft Direction = iota
+	// DirectionRight moves toward larger X.
+	DirectionRight
+	// DirectionUp moves toward smaller Y.
+	DirectionUp
+	// DirectionDown moves toward larger Y.
+	DirectionDown
+)
+
+// Focus tracks which frame has keyboard focus for a [Renderer].
+//
+// Tab order is the document order of focusable frames in the spec tree;
+// frames wrapped with [Unfocusable] are skipped, and an ID that appears more
+// than once is visited at its first position. Directional movement uses the
+// frame rects of the layout cached by the last render, whatever its size.
+// The focused frame is reported to providers through [FrameInfo].Focused.
+type Focus[KID KeelID] struct {
+	renderer *Renderer[KID]
+	current  KID
+	has      bool
+}
+
+// Focus returns the renderer's focus manager, allocating one if needed.
+func (r *Renderer[KID]) Focus() *Focus[KID] {
+	if r == nil {
+		return nil
+	}
+	if r.focus == nil {
+		r.focus = &Focus[KID]{renderer: r}
+	}
+	return r.focus
+}
+
+// Current returns the focused frame ID, and false when nothing is focused.
+func (f *Focus[KID]) Current() (KID, bool) {
+	if f == nil {
+		var zero KID
+		return zero, false
+	}
+	return f.current, f.has
+}
+
+// IsFocused reports whether id has focus.
+func (f *Focus[KID]) IsFocused(id KID) bool {
+	return f != nil && f.has && f.current == id
+}
+
+// Set focuses id and reports whether it is a focusable frame in the spec.
+// Focus is unchanged when it is not.
+func (f *Focus[KID]) Set(id KID) bool {
+	if f == nil {
+		return false
+	}
+	for _, candidate := range f.Order() {
+		if candidate == id {
+			f.focus(id, true)
+			return true
+		}
+	}
+	return false
+}
+
+// Clear removes focus.
+func (f *Focus[KID]) Clear() {
+	if f == nil {
+		return
+	}
+	var zero KID
+	f.focus(zero, false)
+}
+
+// Order returns the focusable frame IDs in tab order.
+func (f *Focus[KID]) Order() []KID {
+	if f == nil || f.renderer == nil || f.renderer.spec == nil {
+		return nil
+	}
+	var order []KID
+	seen := map[KID]bool{}
+	walkFrames(f.renderer.spec, func(frame core.FrameSpec[KID]) {
+		if !isFocusable(frame) || seen[frame.ID()] {
+			return
+		}
+		seen[frame.ID()] = true
+		order = append(order, frame.ID())
+	})
+	return order
+}
+
+// Next moves focus to the next frame in tab order, wrapping at the end.
+// With nothing focused it focuses the first frame.
+func (f *Focus[KID]) Next() (KID, bool) {
+	return f.step(1)
+}
+
+// Prev moves focus to the previous frame in tab order, wrapping at the start.
+// With nothing focused it focuses the last frame.
+func (f *Focus[KID]) Prev() (KID, bool) {
+	return f.step(-1)
+}
+
+func (f *Focus[KID]) step(delta int) (KID, bool) {
+	order := f.Order()
+	if len(order) == 0 {
+		return f.Current()
+	}
+	next := 0
+	if delta < 0 {
+		next = len(order) - 1
+	}
+	for i, id := range order {
+		if f.has && id == f.current {
+			next = (i + delta + len(order)) % len(order)
+			break
+		}
+	}
+	f.focus(order[next], true)
+	return f.current, true
+}
+
+// Move focuses the nearest focusable frame in the given direction from the
+// focused frame's rect in the cached layout. Frames overlapping the focused
+// frame across the direction of travel are preferred, then the smallest gap,
+// then the closest center, then tab order. With nothing focused it focuses the
+// first frame in tab order. Focus is unchanged when there is no candidate or
+// no cached layout, including after an edit to the spec until the next render.
+func (f *Focus[KID]) Move(dir Direction) (KID, bool) {
+	if f == nil {
+		var zero KID
+		return zero, false
+	}
+	if !f.has {
+		return f.step(1)
+	}
+	if f.renderer == nil {
+		return f.Current()
+	}
+	layout, ok := f.renderer.lastLayout()
+	if !ok {
+		return f.Current()
+	}
+
+	var frames []engine.LayoutNode[KID]
+	collectFrames(layout.Root, &frames)
+	var from engine.Rect
+	found := false
+	for _, node := range frames {
+		if node.Frame.ID() == f.current {
+			from, found = node.Rect, true
+			break
+		}
+	}
+	if !found {
+		return f.Current()
+	}
+
+	best := -1
+	var bestScore [3]int
+	for i, node := range frames {
+		id := node.Frame.ID()
+		if id == f.current || !isFocusable(node.Frame) || node.Rect.Width <= 0 || node.Rect.Height <= 0 {
+			continue
+		}
+		score, ok := directionScore(from, node.Rect, dir)
+		if !ok {
+			continue
+		}
+		if best < 0 || lessScore(score, bestScore) {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return f.Current()
+	}
+	f.focus(frames[best].Frame.ID(), true)
+	return f.current, true
+}
+
+// focus moves focus to id (or clears it), letting the renderer detect frame
+// size changes in the frames gaining and losing [StateFocused].
+func (f *Focus[KID]) focus(id KID, has bool) {
+	var ids []KID
+	if has {
+		ids = append(ids, id)
+	}
+	if f.has && (!has || f.current != id) {
+		ids = append(ids, f.current)
+	}
+	apply := func() { f.current, f.has = id, has }
+	if f.renderer == nil {
+		apply()
+		return
+	}
+	f.renderer.restyle(apply, ids...)
+}
+
+// directionScore ranks candidate rect to relative to from when moving in dir.
+// Lower scores are better; ok is false when to is not in that direction.
+func directionScore(from, to engine.Rect, dir Direction) ([3]int, bool) {
+	var gap, start, end, toStart, toEnd int
+	switch dir {
+	case DirectionLeft:
+		gap = from.X - (to.X + to.Width)
+	case DirectionRight:
+		gap = to.X - (from.X + from.Width)
+	case DirectionUp:
+		gap = from.Y - (to.Y + to.Height)
+	case DirectionDown:
+		gap = to.Y - (from.Y + from.Height)
+	default:
+		return [3]int{}, false
+	}
+	if gap < 0 {
+		return [3]int{}, false
+	}
+	if dir == DirectionLeft || dir == DirectionRight {
+		start, end, toStart, toEnd = from.Y, from.Y+from.Height, to.Y, to.Y+to.Height
+	} else {
+		start, end, toStart, toEnd = from.X, from.X+from.Width, to.X, to.X+to.Width
+	}
+	overlap := 1
+	if toStart < end && start < toEnd {
+		overlap = 0
+	}
+	center := (start + end) - (toStart + toEnd)
+	if center < 0 {
+		center = -center
+	}
+	return [3]int{overlap, gap, center}, true
+}
+
+func lessScore(a, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+func isFocusable[KID KeelID](frame core.FrameSpec[KID]) bool {
+	if focusable, ok := frame.(core.Focusable); ok {
+		return focusable.Focusable()
+	}
+	return true
+}
+
+func walkFrames[KID KeelID](spec Spec, visit func(core.FrameSpec[KID])) {
+	switch n := spec.(type) {
+	case core.StackSpec:
+		for i := range n.Len() {
+			if slot, ok := n.Slot(i); ok && slot != nil {
+				walkFrames(slot, visit)
+			}
+		}
+	case core.FrameSpec[KID]:
+		visit(n)
+	}
+}
+
+func collectFrames[KID KeelID](node engine.LayoutNode[KID], frames *[]engine.LayoutNode[KID]) {
+	if node.Kind == engine.NodeFrame {
+		if node.Frame != nil {
+			*frames = append(*frames, node)
+		}
+		return
+	}
+	for _, slot := range node.Slots {
+		collectFrames(slot, frames)
+	}
+}
@@ -0,0 +1,126 @@
+package keel
+
+import (
+	"slices"
+	"testing"
+)
+
+func focusRenderer(t *testing.T) *Renderer[string] {
+	t.Helper()
+	// +------+-----+
+	// | nav  | top |
+	// |      +-----+
+	// |      | bot |
+	// +------+-----+
+	// |   status   |
+	// +------------+
+	layout := Col(FlexUnit(),
+		Row(FlexUnit(),
+			Clip(Fixed(4), "nav"),
+			Col(FlexUnit(),
+				Clip(FlexUnit(), "top"),
+				Clip(FlexUnit(), "bot"),
+			),
+		),
+		Unfocusable(Clip(Fixed(1), "status")),
+	)
+	renderer := NewRenderer(layout, nil, func(id string, info FrameInfo) (string, error) {
+		if info.Focused {
+			return "*", nil
+		}
+		return "", nil
+	})
+	if _, err := renderer.Render(Size{Width: 8, Height: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return renderer
+}
+
+func TestFocusOrder(t *testing.T) {
+	renderer := focusRenderer(t)
+	focus := renderer.Focus()
+	if focus != renderer.Focus() {
+		t.Fatalf("expected focus manager to be reused")
+	}
+	if got, want := focus.Order(), []string{"nav", "top", "bot"}; !slices.Equal(got, want) {
+		t.Fatalf("expected order %v, got %v", want, got)
+	}
+	if _, ok := focus.Current(); ok {
+		t.Fatalf("expected nothing focused")
+	}
+
+	steps := []string{"nav", "top", "bot", "nav"}
+	for _, want := range steps {
+		if got, _ := focus.Next(); got != want {
+			t.Fatalf("expected next %q, got %q", want, got)
+		}
+	}
+	if got, _ := focus.Prev(); got != "bot" {
+		t.Fatalf("expected prev to wrap to bot, got %q", got)
+	}
+
+	if focus.Set("status") {
+		t.Fatalf("expected unfocusable frame to be rejected")
+	}
+	if !focus.Set("top") || !focus.IsFocused("top") {
+		t.Fatalf("expected top focused")
+	}
+	focus.Clear()
+	if got, _ := focus.Prev(); got != "bot" {
+		t.Fatalf("expected prev from nothing to focus the last frame, got %q", got)
+	}
+
+	var missing *Renderer[string]
+	if missing.Focus() != nil {
+		t.Fatalf("expected nil focus for nil renderer")
+	}
+}
+
+func TestFocusMove(t *testing.T) {
+	renderer := focusRenderer(t)
+	focus := renderer.Focus()
+
+	if got, _ := focus.Move(DirectionRight); got != "nav" {
+		t.Fatalf("expected move from nothing to focus nav, got %q", got)
+	}
+	cases := []struct {
+		dir  Direction
+		want string
+	}{
+		{DirectionRight, "top"},
+		{DirectionDown, "bot"},
+		{DirectionDown, "bot"},
+		{DirectionLeft, "nav"},
+		{DirectionLeft, "nav"},
+		{DirectionUp, "nav"},
+		{DirectionRight, "top"},
+		{DirectionUp, "top"},
+	}
+	for i, tc := range cases {
+		if got, _ := focus.Move(tc.dir); got != tc.want {
+			t.Fatalf("step %d: expected %q, got %q", i, tc.want, got)
+		}
+	}
+}
+
+func TestFocusedFrameInfo(t *testing.T) {
+	renderer := focusRenderer(t)
+	renderer.Focus().Set("bot")
+	out, err := renderer.Render(Size{Width: 8, Height: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "        \n        \n    *   \n        \n        "
+	if out != want {
+		t.Fatalf("expected %q, got %q", want, out)
+	}
+
+	arranged, err := renderer.Layout(Size{Width: 8, Height: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	node, _ := arranged.Frame("bot")
+	if !renderer.FrameInfo(node).Focused {
+		t.Fatalf("expected FrameInfo to report focus")
+	}
+}
@@ -39,3 +39,18 @@ func WrapStrict[KID KeelID](extent ExtentConstraint, id KID) FrameSpec[KID] {
 func Overflow[KID KeelID](extent ExtentConstraint, id KID) FrameSpec[KID] {
 	return engine.NewPanelSpec(extent, core.FitOverflow, id)
 }
+
+// Unfocusable wraps a frame so that [Focus] skips it in tab order and
+// directional navigation.
+func Unfocusable[KID KeelID](frame FrameSpec[KID]) FrameSpec[KID] {
+	return unfocusable[KID]{FrameSpec: frame}
+}
+
+type unfocusable[KID KeelID] struct {
+	FrameSpec[KID]
+}
+
+// Focusable implements [core.Focusable].
+func (unfocusable[KID]) Focusable() bool {
+	return false
+}
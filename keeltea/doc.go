@@ -0,0 +1,8 @@
+// Package keeltea adapts keel layouts to Bubble Tea programs.
+//
+// [Model] owns a [keel.Renderer] and implements tea.Model: window size
+// messages drive rendering, mouse messages are routed to the frame under the
+// pointer, and child models can be mounted by frame ID to provide that
+// frame's content. Render errors such as [keel.ExtentTooSmallError] are shown
+// through a configurable [Fallback] view instead of failing the program.
+package keeltea
@@ -0,0 +1,251 @@
+package keeltea
+
+import (
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/trippwill/keel"
+)
+
+// Fallback returns the view shown in place of the layout when rendering at
+// size fails with err.
+type Fallback func(err error, size keel.Size) string
+
+// Model is a Bubble Tea model that renders a keel spec at the terminal size.
+//
+// Mounted child models provide content for their frame: they receive a
+// tea.WindowSizeMsg with their content box size whenever it changes, mouse
+// messages that hit their frame in content-box coordinates (see
+// [keel.ContentHit]), key messages when their frame has focus (see
+// [keel.Focus]), and every other message in mount order. Key messages are
+// broadcast like any other message while nothing is focused or the focused
+// frame has no mounted child. Frames without a mounted child use the model's
+// content provider.
+//
+// Model uses pointer receivers and returns itself from Update.
+type Model[KID keel.KeelID] struct {
+	renderer *keel.Renderer[KID]
+	content  keel.ContentProvider[KID]
+	fallback Fallback
+	children map[KID]tea.Model
+	order    []KID
+	sizes    map[KID]keel.Size
+	size     keel.Size
+	ready    bool
+	revision uint64
+}
+
+// New returns a model that renders spec with the given providers.
+// Either provider may be nil.
+func New[KID keel.KeelID](spec keel.Spec, style keel.StyleProvider[KID], content keel.ContentProvider[KID]) *Model[KID] {
+	m := &Model[KID]{
+		content:  content,
+		fallback: DefaultFallback,
+		children: map[KID]tea.Model{},
+		sizes:    map[KID]keel.Size{},
+	}
+	m.renderer = keel.NewRenderer(spec, style, m.provide)
+	return m
+}
+
+// Renderer returns the model's renderer for configuration.
+// Use [Model.SetContentProvider] rather than replacing the renderer's content
+// provider, which would bypass mounted children.
+func (m *Model[KID]) Renderer() *keel.Renderer[KID] {
+	return m.renderer
+}
+
+// Size returns the last window size, and false before the first
+// tea.WindowSizeMsg.
+func (m *Model[KID]) Size() (keel.Size, bool) {
+	return m.size, m.ready
+}
+
+// SetContentProvider replaces the content provider used for frames without a
+// mounted child.
+func (m *Model[KID]) SetContentProvider(p keel.ContentProvider[KID]) {
+	m.content = p
+}
+
+// SetFallback replaces the view shown when rendering fails.
+// Nil restores [DefaultFallback].
+func (m *Model[KID]) SetFallback(f Fallback) {
+	if f == nil {
+		f = DefaultFallback
+	}
+	m.fallback = f
+}
+
+// Mount attaches a child model as the content of frame id, replacing any
+// child already mounted there. The returned command runs the child's Init and,
+// once the window size is known, sizes it to its content box.
+func (m *Model[KID]) Mount(id KID, child tea.Model) tea.Cmd {
+	if _, ok := m.children[id]; !ok {
+		m.order = append(m.order, id)
+	}
+	m.children[id] = child
+	delete(m.sizes, id)
+	return tea.Batch(child.Init(), m.resize(id))
+}
+
+// Unmount detaches the child model mounted at id, if any.
+func (m *Model[KID]) Unmount(id KID) {
+	if _, ok := m.children[id]; !ok {
+		return
+	}
+	delete(m.children, id)
+	delete(m.sizes, id)
+	for i, mounted := range m.order {
+		if mounted == id {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Child returns the child model mounted at id.
+func (m *Model[KID]) Child(id KID) (tea.Model, bool) {
+	child, ok := m.children[id]
+	return child, ok
+}
+
+// Init runs Init on every mounted child.
+func (m *Model[KID]) Init() tea.Cmd {
+	cmds := make([]tea.Cmd, 0, len(m.order))
+	for _, id := range m.order {
+		cmds = append(cmds, m.children[id].Init())
+	}
+	return tea.Batch(cmds...)
+}
+
+// Update handles window size and mouse messages, sends key messages to the
+// focused child and forwards everything else to the mounted children. When a
+// state or focus change has altered a frame size since the last update (see
+// [keel.Renderer.FrameRevision]), affected children are re-sized.
+func (m *Model[KID]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	cmd := m.handle(msg)
+	if revision := m.renderer.FrameRevision(); revision != m.revision {
+		m.revision = revision
+		cmd = tea.Batch(cmd, m.resizeAll())
+	}
+	return m, cmd
+}
+
+func (m *Model[KID]) handle(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.size = keel.Size{Width: msg.Width, Height: msg.Height}
+		m.ready = true
+		return m.resizeAll()
+	case tea.MouseMsg:
+		return m.routeMouse(msg)
+	case tea.KeyMsg:
+		if id, ok := m.renderer.Focus().Current(); ok {
+			if _, mounted := m.children[id]; mounted {
+				return m.update(id, msg)
+			}
+		}
+	}
+
+	cmds := make([]tea.Cmd, 0, len(m.order))
+	for _, id := range m.order {
+		cmds = append(cmds, m.update(id, msg))
+	}
+	return tea.Batch(cmds...)
+}
+
+// View renders the layout at the last window size, or the fallback view when
+// rendering fails. Partial output from [keel.ErrorModePlaceholder] is shown
+// as is. The view is empty before the first tea.WindowSizeMsg.
+func (m *Model[KID]) View() string {
+	if !m.ready {
+		return ""
+	}
+	out, err := m.renderer.Render(m.size)
+	if err != nil && out == "" {
+		return m.fallback(err, m.size)
+	}
+	return out
+}
+
+// DefaultFallback shows the error message wrapped and clipped to size.
+func DefaultFallback(err error, size keel.Size) string {
+	msg := err.Error()
+	var tooSmall *keel.ExtentTooSmallError
+	if errors.As(err, &tooSmall) {
+		msg = fmt.Sprintf("Terminal too small (%dx%d)\n%s", size.Width, size.Height, msg)
+	}
+	return gloss.NewStyle().
+		Width(size.Width).
+		MaxWidth(size.Width).
+		MaxHeight(size.Height).
+		Render(msg)
+}
+
+func (m *Model[KID]) provide(id KID, info keel.FrameInfo) (string, error) {
+	if child, ok := m.children[id]; ok {
+		return child.View(), nil
+	}
+	if m.content == nil {
+		return "", &keel.UnknownFrameIDError{ID: id}
+	}
+	return m.content(id, info)
+}
+
+func (m *Model[KID]) resizeAll() tea.Cmd {
+	cmds := make([]tea.Cmd, 0, len(m.order))
+	for _, id := range m.order {
+		cmds = append(cmds, m.resize(id))
+	}
+	return tea.Batch(cmds...)
+}
+
+// resize sends the child at id its content box size if it changed.
+func (m *Model[KID]) resize(id KID) tea.Cmd {
+	if !m.ready {
+		return nil
+	}
+	layout, err := m.renderer.Layout(m.size)
+	if err != nil {
+		return nil
+	}
+	node, ok := layout.Frame(id)
+	if !ok {
+		return nil
+	}
+	info := m.renderer.FrameInfo(node)
+	size := keel.Size{Width: info.ContentWidth, Height: info.ContentHeight}
+	if last, ok := m.sizes[id]; ok && last == size {
+		return nil
+	}
+	m.sizes[id] = size
+	return m.update(id, tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+}
+
+// routeMouse forwards a mouse message to the child mounted at the frame under
+// the pointer, translated to content-box coordinates.
+func (m *Model[KID]) routeMouse(msg tea.MouseMsg) tea.Cmd {
+	if !m.ready {
+		return nil
+	}
+	if _, err := m.renderer.Layout(m.size); err != nil {
+		return nil
+	}
+	hit, ok := m.renderer.ContentAt(msg.X, msg.Y)
+	if !ok {
+		return nil
+	}
+	if _, ok := m.children[hit.ID]; !ok {
+		return nil
+	}
+	msg.X, msg.Y = hit.X, hit.Y
+	return m.update(hit.ID, msg)
+}
+
+func (m *Model[KID]) update(id KID, msg tea.Msg) tea.Cmd {
+	child, cmd := m.children[id].Update(msg)
+	m.children[id] = child
+	return cmd
+}
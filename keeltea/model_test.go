@@ -0,0 +1,191 @@
+package keeltea
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/trippwill/keel"
+)
+
+type recorder struct {
+	view string
+	msgs []tea.Msg
+}
+
+func (r *recorder) Init() tea.Cmd { return nil }
+
+func (r *recorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	r.msgs = append(r.msgs, msg)
+	return r, nil
+}
+
+func (r *recorder) View() string { return r.view }
+
+func testModel() *Model[string] {
+	layout := keel.Row(keel.FlexUnit(),
+		keel.Clip(keel.Fixed(3), "nav"),
+		keel.Clip(keel.FlexUnit(), "body"),
+	)
+	style := gloss.NewStyle().Padding(0, 1)
+	return New(layout, func(id string) *gloss.Style {
+		if id == "body" {
+			return &style
+		}
+		return nil
+	}, func(id string, _ keel.FrameInfo) (string, error) {
+		return id, nil
+	})
+}
+
+func TestModelRendersAtWindowSize(t *testing.T) {
+	m := testModel()
+	if got := m.View(); got != "" {
+		t.Fatalf("expected empty view before sizing, got %q", got)
+	}
+	m.Update(tea.WindowSizeMsg{Width: 10, Height: 1})
+	if got, want := m.View(), "nav body  "; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if size, ok := m.Size(); !ok || size != (keel.Size{Width: 10, Height: 1}) {
+		t.Fatalf("unexpected size %+v", size)
+	}
+}
+
+func TestModelMountedChild(t *testing.T) {
+	m := testModel()
+	child := &recorder{view: "kid"}
+	m.Mount("body", child)
+	if len(child.msgs) != 0 {
+		t.Fatalf("expected no messages before sizing, got %v", child.msgs)
+	}
+
+	m.Update(tea.WindowSizeMsg{Width: 10, Height: 2})
+	if got, want := m.View(), "nav kid   \n          "; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if len(child.msgs) != 1 || child.msgs[0] != (tea.WindowSizeMsg{Width: 5, Height: 2}) {
+		t.Fatalf("expected content box size, got %v", child.msgs)
+	}
+
+	m.Update(tea.WindowSizeMsg{Width: 10, Height: 2})
+	if len(child.msgs) != 1 {
+		t.Fatalf("expected unchanged size not to be resent, got %v", child.msgs)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(child.msgs) != 2 {
+		t.Fatalf("expected key message forwarded, got %v", child.msgs)
+	}
+
+	m.Unmount("body")
+	if got, want := m.View(), "nav body  \n          "; got != want {
+		t.Fatalf("expected %q after unmount, got %q", want, got)
+	}
+}
+
+func TestModelRoutesMouse(t *testing.T) {
+	m := testModel()
+	nav := &recorder{}
+	body := &recorder{}
+	m.Mount("nav", nav)
+	m.Mount("body", body)
+	m.Update(tea.WindowSizeMsg{Width: 10, Height: 2})
+	nav.msgs, body.msgs = nil, nil
+
+	m.Update(tea.MouseMsg{X: 5, Y: 1, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	if len(nav.msgs) != 0 || len(body.msgs) != 1 {
+		t.Fatalf("expected mouse routed to body, got nav %v body %v", nav.msgs, body.msgs)
+	}
+	got := body.msgs[0].(tea.MouseMsg)
+	if got.X != 1 || got.Y != 1 || got.Button != tea.MouseButtonLeft {
+		t.Fatalf("expected content-box coordinates, got %+v", got)
+	}
+
+	m.Update(tea.MouseMsg{X: 20, Y: 0})
+	if len(nav.msgs) != 0 || len(body.msgs) != 1 {
+		t.Fatalf("expected mouse outside the layout to be dropped")
+	}
+}
+
+func TestModelFallback(t *testing.T) {
+	m := testModel()
+	m.Update(tea.WindowSizeMsg{Width: 2, Height: 1})
+	if got := m.View(); !strings.HasPrefix(got, "Te") {
+		t.Fatalf("expected default fallback, got %q", got)
+	}
+
+	var seen error
+	m.SetFallback(func(err error, size keel.Size) string {
+		seen = err
+		return "small"
+	})
+	if got := m.View(); got != "small" {
+		t.Fatalf("expected custom fallback, got %q", got)
+	}
+	if !errors.Is(seen, keel.ErrExtentTooSmall) {
+		t.Fatalf("expected extent too small error, got %v", seen)
+	}
+}
+
+func TestModelKeysGoToFocusedChild(t *testing.T) {
+	m := testModel()
+	nav := &recorder{}
+	body := &recorder{}
+	m.Mount("nav", nav)
+	m.Mount("body", body)
+	m.Update(tea.WindowSizeMsg{Width: 10, Height: 2})
+	nav.msgs, body.msgs = nil, nil
+
+	m.Renderer().Focus().Set("body")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(nav.msgs) != 0 || len(body.msgs) != 1 {
+		t.Fatalf("expected key sent to focused body, got nav %v body %v", nav.msgs, body.msgs)
+	}
+
+	m.Unmount("body")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(nav.msgs) != 1 {
+		t.Fatalf("expected key for unmounted focused frame to be broadcast, got %v", nav.msgs)
+	}
+}
+
+func TestModelResizesOnFrameRevision(t *testing.T) {
+	m := testModel()
+	m.Renderer().SetStatefulStyleProvider(func(id string, state keel.FrameState) *gloss.Style {
+		style := gloss.NewStyle()
+		if state.Has(keel.StateFocused) {
+			style = style.Padding(0, 1)
+		}
+		return &style
+	})
+	body := &recorder{}
+	m.Mount("body", body)
+	m.Update(tea.WindowSizeMsg{Width: 10, Height: 2})
+	if len(body.msgs) != 1 || body.msgs[0] != (tea.WindowSizeMsg{Width: 7, Height: 2}) {
+		t.Fatalf("expected unpadded content box, got %v", body.msgs)
+	}
+
+	m.Renderer().Focus().Set("body")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(body.msgs) != 3 || body.msgs[2] != (tea.WindowSizeMsg{Width: 5, Height: 2}) {
+		t.Fatalf("expected re-size after focus padding, got %v", body.msgs)
+	}
+}
+
+func TestModelShowsPartialOutput(t *testing.T) {
+	m := testModel()
+	m.SetContentProvider(func(id string, _ keel.FrameInfo) (string, error) {
+		if id == "nav" {
+			return "", errors.New("broken")
+		}
+		return id, nil
+	})
+	m.Renderer().Config().SetErrorMode(keel.ErrorModePlaceholder)
+	m.Update(tea.WindowSizeMsg{Width: 10, Height: 1})
+	if got := m.View(); !strings.HasPrefix(got, "!") || !strings.Contains(got, "body") {
+		t.Fatalf("expected placeholder with partial output, got %q", got)
+	}
+}
@@ -0,0 +1,5 @@
+// Package keeltest provides helpers for testing keel layouts.
+//
+// Use [Sweep] to render a spec across a range of sizes and report the sizes
+// that fail or produce output of the wrong dimensions.
+package keeltest
@@ -0,0 +1,212 @@
+package keeltest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/trippwill/keel"
+)
+
+// Failure records a size at which rendering returned an error.
+type Failure struct {
+	Size keel.Size
+	Err  error
+}
+
+// Mismatch records a size at which rendering succeeded but the output
+// dimensions differ from the requested size.
+type Mismatch struct {
+	Size keel.Size
+	Got  keel.Size
+}
+
+// Group collects failures that share an error kind and frame.
+type Group struct {
+	Kind  string      // Error kind, e.g. "extent too small (content)" or "spec slot"
+	Frame string      // Layout path and frame ID of the failing node, if known
+	Sizes []keel.Size // Failing sizes in sweep order
+}
+
+// Report is the result of a [Sweep].
+type Report struct {
+	Min, Max   keel.Size
+	Failures   []Failure
+	Mismatches []Mismatch
+}
+
+// Sweep renders spec at every size from minSize to maxSize (inclusive on both
+// axes) and reports the sizes that fail or whose output is not exactly the
+// requested width and height.
+func Sweep[KID keel.KeelID](
+	spec keel.Spec,
+	style keel.StyleProvider[KID],
+	content keel.ContentProvider[KID],
+	minSize, maxSize keel.Size,
+) *Report {
+	report := &Report{Min: minSize, Max: maxSize}
+	renderer := keel.NewRenderer(spec, style, content)
+	for height := minSize.Height; height <= maxSize.Height; height++ {
+		for width := minSize.Width; width <= maxSize.Width; width++ {
+			size := keel.Size{Width: width, Height: height}
+			out, err := renderer.Render(size)
+			if err != nil {
+				report.Failures = append(report.Failures, Failure{Size: size, Err: err})
+				continue
+			}
+			got := measure(out, size)
+			if got != size {
+				report.Mismatches = append(report.Mismatches, Mismatch{Size: size, Got: got})
+			}
+		}
+	}
+	return report
+}
+
+// OK reports whether every size rendered with the requested dimensions.
+func (r *Report) OK() bool {
+	return len(r.Failures) == 0 && len(r.Mismatches) == 0
+}
+
+// Groups returns failures grouped by error kind and frame, in order of first
+// occurrence.
+func (r *Report) Groups() []Group {
+	var groups []Group
+	index := map[[2]string]int{}
+	for _, failure := range r.Failures {
+		kind, frame := classify(failure.Err)
+		key := [2]string{kind, frame}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, Group{Kind: kind, Frame: frame})
+		}
+		groups[i].Sizes = append(groups[i].Sizes, failure.Size)
+	}
+	return groups
+}
+
+// Matrix formats a compact pass/fail matrix with one row per height and one
+// column per width. Cells are '.' for pass, 'x' for failure and '~' for a
+// dimension mismatch. The header shows the last digit of each width.
+func (r *Report) Matrix() string {
+	failed := map[keel.Size]bool{}
+	for _, failure := range r.Failures {
+		failed[failure.Size] = true
+	}
+	mismatched := map[keel.Size]bool{}
+	for _, mismatch := range r.Mismatches {
+		mismatched[mismatch.Size] = true
+	}
+
+	label := len(strconv.Itoa(r.Max.Height))
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", label+1))
+	for width := r.Min.Width; width <= r.Max.Width; width++ {
+		b.WriteByte(byte('0' + width%10))
+	}
+	for height := r.Min.Height; height <= r.Max.Height; height++ {
+		fmt.Fprintf(&b, "\n%*d ", label, height)
+		for width := r.Min.Width; width <= r.Max.Width; width++ {
+			size := keel.Size{Width: width, Height: height}
+			switch {
+			case failed[size]:
+				b.WriteByte('x')
+			case mismatched[size]:
+				b.WriteByte('~')
+			default:
+				b.WriteByte('.')
+			}
+		}
+	}
+	return b.String()
+}
+
+// String summarizes the report: grouped failures, mismatches and the matrix.
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(
+		&b,
+		"sweep %dx%d..%dx%d: %d failed, %d mismatched\n",
+		r.Min.Width, r.Min.Height, r.Max.Width, r.Max.Height,
+		len(r.Failures), len(r.Mismatches),
+	)
+	for _, group := range r.Groups() {
+		frame := ""
+		if group.Frame != "" {
+			frame = " at " + group.Frame
+		}
+		fmt.Fprintf(&b, "  %s%s: %d sizes, first %s\n", group.Kind, frame, len(group.Sizes), formatSize(group.Sizes[0]))
+	}
+	for _, mismatch := range r.Mismatches {
+		fmt.Fprintf(&b, "  size mismatch at %s: got %s\n", formatSize(mismatch.Size), formatSize(mismatch.Got))
+	}
+	b.WriteString(r.Matrix())
+	return b.String()
+}
+
+// Err returns nil when the report is OK, or an error carrying the summary.
+func (r *Report) Err() error {
+	if r.OK() {
+		return nil
+	}
+	return errors.New(r.String())
+}
+
+// Check reports a test error with the summary when the report is not OK.
+func (r *Report) Check(tb testing.TB) {
+	tb.Helper()
+	if err := r.Err(); err != nil {
+		tb.Error(err)
+	}
+}
+
+func classify(err error) (string, string) {
+	var tooSmall *keel.ExtentTooSmallError
+	if errors.As(err, &tooSmall) {
+		kind := "extent too small"
+		if tooSmall.Reason != "" {
+			kind += " (" + tooSmall.Reason + ")"
+		}
+		return kind, location(tooSmall.Path, tooSmall.ID)
+	}
+	var specErr *keel.SpecError
+	if errors.As(err, &specErr) {
+		return strings.TrimSpace("spec " + specErr.Kind), location(specErr.Path, specErr.ID)
+	}
+	return err.Error(), ""
+}
+
+func location(path string, id any) string {
+	if id == nil {
+		return path
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s (frame %v)", path, id))
+}
+
+// measure returns the dimensions of rendered output: one row per line and the
+// width of the first line that is not want.Width wide, or want.Width when every
+// line is. Output for a zero-height size has no rows, so its width cannot be
+// observed; any output without a line break measures as want.
+func measure(out string, want keel.Size) keel.Size {
+	if want.Height == 0 && !strings.Contains(out, "\n") {
+		return want
+	}
+	lines := strings.Split(out, "\n")
+	got := keel.Size{Width: want.Width, Height: len(lines)}
+	for _, line := range lines {
+		if w := ansi.StringWidth(line); w != want.Width {
+			got.Width = w
+			break
+		}
+	}
+	return got
+}
+
+func formatSize(size keel.Size) string {
+	return fmt.Sprintf("%dx%d", size.Width, size.Height)
+}
@@ -0,0 +1,163 @@
+package keeltest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/trippwill/keel"
+	"github.com/trippwill/keel/examples"
+)
+
+func content(result string) keel.ContentProvider[string] {
+	return func(string, keel.FrameInfo) (string, error) {
+		return result, nil
+	}
+}
+
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Error(args ...any) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func TestSweepPasses(t *testing.T) {
+	layout := keel.Row(keel.FlexUnit(),
+		keel.Clip(keel.FlexUnit(), "a"),
+		keel.Clip(keel.FlexUnit(), "b"),
+	)
+	report := Sweep(layout, nil, content("hello"), keel.Size{Width: 2, Height: 1}, keel.Size{Width: 12, Height: 4})
+	if !report.OK() {
+		t.Fatalf("expected sweep to pass:\n%s", report)
+	}
+	if report.Err() != nil {
+		t.Fatalf("expected nil error")
+	}
+	report.Check(t)
+}
+
+func TestSweepFromZero(t *testing.T) {
+	layout := keel.Row(keel.FlexUnit(),
+		keel.Clip(keel.FlexUnit(), "a"),
+		keel.Clip(keel.FlexUnit(), "b"),
+	)
+	report := Sweep(layout, nil, content(""), keel.Size{}, keel.Size{Width: 3, Height: 2})
+	if !report.OK() {
+		t.Fatalf("expected sweep from 0x0 to pass:\n%s", report)
+	}
+}
+
+func TestSweepExampleSplit(t *testing.T) {
+	report := Sweep(
+		examples.ExampleSplit(),
+		examples.ExampleSplitStyleProvider,
+		examples.ExampleSplitContentProvider,
+		keel.Size{Width: 70, Height: 13},
+		keel.Size{Width: 90, Height: 16},
+	)
+	report.Check(t)
+}
+
+func TestSweepExampleSplitRegistry(t *testing.T) {
+	registry := examples.ExampleSplitRegistry()
+	if err := registry.Check(examples.ExampleSplit()); err != nil {
+		t.Fatalf("expected example registry to match its spec, got %v", err)
+	}
+	report := Sweep(
+		examples.ExampleSplit(),
+		registry.StyleProvider(),
+		registry.ContentProvider(),
+		keel.Size{Width: 70, Height: 13},
+		keel.Size{Width: 72, Height: 14},
+	)
+	report.Check(t)
+}
+
+func TestSweepGroupsFailures(t *testing.T) {
+	layout := keel.Row(keel.FlexUnit(),
+		keel.Exact(keel.Fixed(3), "a"),
+		keel.Clip(keel.FlexUnit(), "b"),
+	)
+	provider := func(id string, _ keel.FrameInfo) (string, error) {
+		if id == "a" {
+			return "abcd", nil
+		}
+		return "", nil
+	}
+	report := Sweep(layout, nil, provider, keel.Size{Width: 2, Height: 1}, keel.Size{Width: 4, Height: 2})
+	if report.OK() {
+		t.Fatalf("expected failures")
+	}
+	if len(report.Failures) != 6 {
+		t.Fatalf("expected 6 failures, got %d", len(report.Failures))
+	}
+
+	groups := report.Groups()
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %+v", groups)
+	}
+	if groups[0].Kind != "extent too small (allocation)" || groups[0].Frame != "/" || len(groups[0].Sizes) != 2 {
+		t.Fatalf("unexpected allocation group: %+v", groups[0])
+	}
+	if groups[1].Kind != "extent too small (content)" || groups[1].Frame != "/0 (frame a)" || len(groups[1].Sizes) != 4 {
+		t.Fatalf("unexpected content group: %+v", groups[1])
+	}
+
+	tb := &recordingTB{TB: t}
+	report.Check(tb)
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "6 failed, 0 mismatched") {
+		t.Fatalf("expected summary error, got %q", tb.errors)
+	}
+}
+
+func TestSweepReportsMismatches(t *testing.T) {
+	layout := keel.Overflow(keel.FlexUnit(), "a")
+	report := Sweep(layout, nil, content("abcd"), keel.Size{Width: 2, Height: 1}, keel.Size{Width: 4, Height: 1})
+	if len(report.Failures) != 0 {
+		t.Fatalf("expected no failures, got %+v", report.Failures)
+	}
+	if len(report.Mismatches) != 2 {
+		t.Fatalf("expected 2 mismatches, got %+v", report.Mismatches)
+	}
+	want := Mismatch{Size: keel.Size{Width: 2, Height: 1}, Got: keel.Size{Width: 2, Height: 2}}
+	if report.Mismatches[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, report.Mismatches[0])
+	}
+}
+
+func TestMeasureChecksEveryLine(t *testing.T) {
+	want := keel.Size{Width: 4, Height: 3}
+	if got := measure("abcd\nab\nabcd", want); got != (keel.Size{Width: 2, Height: 3}) {
+		t.Fatalf("expected short line to be reported, got %+v", got)
+	}
+	if got := measure("abcd\nabcd\nabcd", want); got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestSweepMatrix(t *testing.T) {
+	layout := keel.Row(keel.FlexUnit(),
+		keel.Exact(keel.Fixed(2), "a"),
+		keel.Overflow(keel.FlexUnit(), "b"),
+	)
+	provider := func(id string, _ keel.FrameInfo) (string, error) {
+		if id == "b" {
+			return "abc", nil
+		}
+		return "", nil
+	}
+	report := Sweep(layout, nil, provider, keel.Size{Width: 1, Height: 9}, keel.Size{Width: 6, Height: 10})
+	want := strings.Join([]string{
+		"   123456",
+		" 9 x~....",
+		"10 x~....",
+	}, "\n")
+	if got := report.Matrix(); got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+}
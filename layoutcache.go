@@ -0,0 +1,107 @@
+package keel
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/trippwill/keel/engine"
+	"github.com/trippwill/keel/logging"
+)
+
+// DefaultLayoutCacheSize is the number of arranged layouts a renderer keeps
+// when [Config.SetLayoutCacheSize] is not set.
+const DefaultLayoutCacheSize = 4
+
+// LayoutCacheStats reports the activity of a renderer's layout cache.
+type LayoutCacheStats struct {
+	Hits, Misses, Evictions uint64
+	Len, Cap                int
+}
+
+type layoutKey struct {
+	size Size
+}
+
+type layoutEntry[KID KeelID] struct {
+	key    layoutKey
+	layout engine.Layout[KID]
+}
+
+// layoutCache is a small LRU of arranged layouts, most recent first.
+type layoutCache[KID KeelID] struct {
+	entries    []layoutEntry[KID]
+	stats      LayoutCacheStats
+	generation uint64
+}
+
+// sync drops every entry when the spec generation has changed since the
+// entries were arranged. Generations only increase, so stale entries can never
+// be hit again.
+func (c *layoutCache[KID]) sync(generation uint64) {
+	if generation != c.generation {
+		c.reset()
+		c.generation = generation
+	}
+}
+
+func (c *layoutCache[KID]) get(key layoutKey) (engine.Layout[KID], bool) {
+	for i, entry := range c.entries {
+		if entry.key != key {
+			continue
+		}
+		copy(c.entries[1:i+1], c.entries[:i])
+		c.entries[0] = entry
+		c.stats.Hits++
+		return entry.layout, true
+	}
+	c.stats.Misses++
+	return engine.Layout[KID]{}, false
+}
+
+func (c *layoutCache[KID]) put(key layoutKey, layout engine.Layout[KID], capacity int) {
+	if len(c.entries) >= capacity {
+		evicted := len(c.entries) - capacity + 1
+		clear(c.entries[capacity-1:])
+		c.entries = c.entries[:capacity-1]
+		c.stats.Evictions += uint64(evicted)
+	}
+	c.entries = append(c.entries, layoutEntry[KID]{})
+	copy(c.entries[1:], c.entries)
+	c.entries[0] = layoutEntry[KID]{key: key, layout: layout}
+}
+
+func (c *layoutCache[KID]) reset() {
+	clear(c.entries)
+	c.entries = c.entries[:0]
+}
+
+// LayoutCacheStats returns the hits, misses and evictions of the renderer's
+// layout cache, with its current length and capacity.
+func (r *Renderer[KID]) LayoutCacheStats() LayoutCacheStats {
+	if r == nil {
+		return LayoutCacheStats{}
+	}
+	r.layoutMu.Lock()
+	defer r.layoutMu.Unlock()
+	stats := r.layouts.stats
+	stats.Len = len(r.layouts.entries)
+	stats.Cap = r.config.LayoutCacheSize()
+	return stats
+}
+
+// logLayoutCache logs a layout cache lookup once its outcome is known, after
+// the stack.alloc events of a miss.
+func (r *Renderer[KID]) logLayoutCache(ctx context.Context, key layoutKey, hit bool) {
+	logEvent(
+		ctx,
+		rendererLogger(r),
+		"",
+		logging.EventLayoutCache,
+		slog.Bool("hit", hit),
+		slog.Int("width", key.size.Width),
+		slog.Int("height", key.size.Height),
+		slog.Uint64("hits", r.layouts.stats.Hits),
+		slog.Uint64("misses", r.layouts.stats.Misses),
+		slog.Uint64("evictions", r.layouts.stats.Evictions),
+	)
+}
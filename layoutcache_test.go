@@ -0,0 +1,155 @@
+package keel
+
+import (
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/trippwill/keel/core"
+	"github.com/trippwill/keel/logging"
+)
+
+func TestLayoutCacheLRU(t *testing.T) {
+	spec := &countingStack{
+		axis:  core.AxisHorizontal,
+		slots: []Spec{Exact(FlexUnit(), "a")},
+	}
+	renderer := NewRenderer(spec, nil, makeContentProvider("ok"))
+	renderer.Config().SetLayoutCacheSize(2)
+	handler, entries := newCaptureHandler()
+	renderer.Config().SetLogger(slog.New(handler))
+
+	small := Size{Width: 2, Height: 1}
+	medium := Size{Width: 3, Height: 1}
+	large := Size{Width: 4, Height: 1}
+	arranges := func(size Size) int {
+		t.Helper()
+		before := spec.calls
+		if _, err := renderer.Render(size); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return spec.calls - before
+	}
+
+	if arranges(small) == 0 || arranges(medium) == 0 {
+		t.Fatalf("expected first renders to arrange")
+	}
+	if arranges(small) != 0 || arranges(medium) != 0 {
+		t.Fatalf("expected cached layouts for both sizes")
+	}
+	if arranges(large) == 0 {
+		t.Fatalf("expected a new size to arrange")
+	}
+	if arranges(medium) != 0 {
+		t.Fatalf("expected the recently used size to stay cached")
+	}
+	if arranges(small) == 0 {
+		t.Fatalf("expected the least recently used size to be evicted")
+	}
+
+	want := LayoutCacheStats{Hits: 3, Misses: 4, Evictions: 2, Len: 2, Cap: 2}
+	if got := renderer.LayoutCacheStats(); got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	var events []logEntry
+	for _, entry := range *entries {
+		if entry.attrs["event"] == string(logging.EventLayoutCache) {
+			events = append(events, entry)
+		}
+	}
+	if len(events) != 7 || events[0].attrs["hit"] != false || events[2].attrs["hit"] != true {
+		t.Fatalf("unexpected layout.cache events %+v", events)
+	}
+	if events[6].attrs["evictions"] != uint64(2) {
+		t.Fatalf("expected eviction count in log, got %+v", events[6].attrs)
+	}
+
+	renderer.Invalidate()
+	if got := renderer.LayoutCacheStats(); got.Len != 0 || got.Hits != 3 {
+		t.Fatalf("expected invalidate to empty the cache and keep stats, got %+v", got)
+	}
+	if arranges(medium) == 0 {
+		t.Fatalf("expected re-arrange after invalidate")
+	}
+}
+
+func TestLayoutCacheSizeDefault(t *testing.T) {
+	config := NewConfig()
+	if config.LayoutCacheSize() != DefaultLayoutCacheSize {
+		t.Fatalf("expected default layout cache size")
+	}
+	config.SetLayoutCacheSize(16)
+	if config.LayoutCacheSize() != 16 {
+		t.Fatalf("expected layout cache size 16")
+	}
+	config.SetLayoutCacheSize(0)
+	if config.LayoutCacheSize() != DefaultLayoutCacheSize {
+		t.Fatalf("expected default after reset")
+	}
+	var missing *Renderer[string]
+	if missing.LayoutCacheStats() != (LayoutCacheStats{}) {
+		t.Fatalf("expected zero stats for nil renderer")
+	}
+}
+
+func TestLayoutCacheFollowsSpecGeneration(t *testing.T) {
+	row := MutableRow(FlexUnit(), Exact(FlexUnit(), "a"))
+	renderer := NewRenderer(Spec(row), nil, makeContentProvider("x"))
+	size := Size{Width: 4, Height: 1}
+
+	first, err := renderer.Layout(size)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.Root.Slots) != 1 {
+		t.Fatalf("expected 1 slot, got %d", len(first.Root.Slots))
+	}
+	if err := row.Insert(1, Exact(FlexUnit(), "b")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := renderer.Layout(size)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(second.Root.Slots) != 2 {
+		t.Fatalf("expected re-arranged layout with 2 slots, got %d", len(second.Root.Slots))
+	}
+	if stats := renderer.LayoutCacheStats(); stats.Misses != 2 || stats.Len != 1 {
+		t.Fatalf("expected 2 misses and 1 entry, got %+v", stats)
+	}
+	out, err := renderer.Render(size)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "x x " {
+		t.Fatalf("unexpected output %q", out)
+	}
+}
+
+func TestLayoutCacheConcurrentRender(t *testing.T) {
+	spec := Row(FlexUnit(), Exact(FlexUnit(), "a"), Exact(FlexUnit(), "b"))
+	renderer := NewRenderer(spec, nil, makeContentProvider("x"))
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func(width int) {
+			defer wg.Done()
+			for range 50 {
+				out, err := renderer.Render(Size{Width: width, Height: 1})
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				if len(out) != width {
+					t.Errorf("expected width %d, got %q", width, out)
+					return
+				}
+				renderer.LayoutCacheStats()
+				renderer.ContentAt(0, 0)
+			}
+		}(4 + i)
+	}
+	wg.Wait()
+}
@@ -9,32 +9,57 @@ import (
 type Event string
 
 const (
-	EventStackAlloc  Event = "stack.alloc"
-	EventFrameRender Event = "frame.render"
-	EventRenderError Event = "render.error"
+	EventStackAlloc     Event = "stack.alloc"
+	EventFrameRender    Event = "frame.render"
+	EventRenderError    Event = "render.error"
+	EventRenderRepair   Event = "render.repair"
+	EventFrameRestyle   Event = "frame.restyle"
+	EventFrameTiming    Event = "frame.timing"
+	EventFrameCache     Event = "frame.cache"
+	EventLayoutCache    Event = "layout.cache"
+	EventProviderPanic  Event = "provider.panic"
+	EventProviderTiming Event = "provider.timing"
 )
 
+// LevelTrace is the level for verbose render traces such as allocation
+// explanations. It sits below [slog.LevelDebug].
+const LevelTrace = slog.LevelDebug - 4
+
+// Enabled reports whether the logger is non-nil and enabled at the level.
+func Enabled(logger *slog.Logger, level slog.Level) bool {
+	return logger != nil && logger.Enabled(context.Background(), level)
+}
+
 // LogEvent logs a structured render event to the provided logger.
 func LogEvent(logger *slog.Logger, level slog.Level, event Event, path string, attrs ...slog.Attr) {
-	if logger == nil {
-		return
-	}
-	if !logger.Enabled(context.Background(), level) {
+	LogEventContext(context.Background(), logger, level, event, path, attrs...)
+}
+
+// LogEventContext is [LogEvent] with a context passed through to the handler,
+// for example to carry trace IDs.
+func LogEventContext(ctx context.Context, logger *slog.Logger, level slog.Level, event Event, path string, attrs ...slog.Attr) {
+	if logger == nil || !logger.Enabled(ctx, level) {
 		return
 	}
 	if path != "" {
 		attrs = append(attrs, slog.String("path", path))
 	}
 	attrs = append(attrs, slog.String("event", string(event)))
-	logger.LogAttrs(context.Background(), level, "keel.render", attrs...)
+	logger.LogAttrs(ctx, level, "keel.render", attrs...)
 }
 
 // LogError logs a render error event at error level.
 func LogError(logger *slog.Logger, path string, stage string, err error) {
+	LogErrorContext(context.Background(), logger, path, stage, err)
+}
+
+// LogErrorContext is [LogError] with a context passed through to the handler.
+func LogErrorContext(ctx context.Context, logger *slog.Logger, path string, stage string, err error) {
 	if err == nil {
 		return
 	}
-	LogEvent(
+	LogEventContext(
+		ctx,
 		logger,
 		slog.LevelError,
 		EventRenderError,
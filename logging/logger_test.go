@@ -3,12 +3,14 @@ package logging
 import (
 	"context"
 	"errors"
+	"io"
 	"log/slog"
 	"strings"
 	"testing"
 )
 
 type logEntry struct {
+	ctx   context.Context
 	level slog.Level
 	msg   string
 	attrs map[string]any
@@ -29,7 +31,7 @@ func (h *captureHandler) Enabled(context.Context, slog.Level) bool {
 	return true
 }
 
-func (h *captureHandler) Handle(_ context.Context, record slog.Record) error {
+func (h *captureHandler) Handle(ctx context.Context, record slog.Record) error {
 	attrs := map[string]any{}
 	groupPrefix := strings.Join(h.groups, ".")
 	addAttr := func(attr slog.Attr) {
@@ -49,6 +51,7 @@ func (h *captureHandler) Handle(_ context.Context, record slog.Record) error {
 	})
 
 	*h.entries = append(*h.entries, logEntry{
+		ctx:   ctx,
 		level: record.Level,
 		msg:   record.Message,
 		attrs: attrs,
@@ -124,3 +127,36 @@ func TestLogError(t *testing.T) {
 		t.Fatalf("unexpected err attr: %v", entry.attrs["err"])
 	}
 }
+
+func TestLogContextPassedToHandler(t *testing.T) {
+	type traceKey struct{}
+	handler, entries := newCaptureHandler()
+	logger := slog.New(handler)
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace-1")
+
+	LogEventContext(ctx, logger, slog.LevelDebug, EventFrameRender, "/0")
+	LogErrorContext(ctx, logger, "/0", "frame.content", errors.New("boom"))
+	LogErrorContext(ctx, logger, "/0", "frame.content", nil)
+
+	if len(*entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(*entries))
+	}
+	for _, entry := range *entries {
+		if entry.ctx.Value(traceKey{}) != "trace-1" {
+			t.Fatalf("expected context passed to handler")
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	if Enabled(nil, slog.LevelError) {
+		t.Fatalf("expected nil logger disabled")
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	if !Enabled(logger, slog.LevelDebug) {
+		t.Fatalf("expected debug enabled")
+	}
+	if Enabled(logger, LevelTrace) {
+		t.Fatalf("expected trace disabled")
+	}
+}
@@ -0,0 +1,318 @@
+package keel
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/trippwill/keel/core"
+	"github.com/trippwill/keel/engine"
+	"github.com/trippwill/keel/logging"
+)
+
+// ContentMiddleware wraps a content provider with additional behavior.
+//
+// The renderer composes its middleware around the content provider once, and
+// again whenever middleware or providers change, so a middleware may wrap
+// providers more than once and must keep any state in the middleware itself
+// rather than in the wrapper it returns. Wrapped providers receive the render
+// context; with [Config.SetConcurrency], they are called from several
+// goroutines.
+type ContentMiddleware[KID KeelID] func(next ContentProviderCtx[KID]) ContentProviderCtx[KID]
+
+// StyleMiddleware wraps a style provider with additional behavior.
+// The same composition rules as [ContentMiddleware] apply. Styles looked up
+// outside a render, for example by [Renderer.FrameInfo], receive
+// [context.Background].
+type StyleMiddleware[KID KeelID] func(next StyleProviderCtx[KID]) StyleProviderCtx[KID]
+
+// Use appends content middleware to the renderer. Middleware runs in the
+// order it was added: the first middleware sees each call first.
+// When debug is enabled, [DebugContent] is applied innermost, in place of the
+// content provider.
+func (r *Renderer[KID]) Use(middleware ...ContentMiddleware[KID]) {
+	if r == nil {
+		return
+	}
+	r.middleware = append(r.middleware, middleware...)
+	r.buildContentChain()
+	r.clearFrameCache()
+}
+
+// UseStyle appends style middleware to the renderer, in the same order as
+// [Renderer.Use]. Styles can change frame sizes, so UseStyle bumps
+// [Renderer.FrameRevision].
+func (r *Renderer[KID]) UseStyle(middleware ...StyleMiddleware[KID]) {
+	if r == nil {
+		return
+	}
+	r.styleMiddleware = append(r.styleMiddleware, middleware...)
+	r.buildStyleChain()
+	r.revision++
+}
+
+// DebugContent replaces the wrapped provider with
+// [core.DebugContentProvider]. It is the middleware behind [Config.SetDebug].
+func DebugContent[KID KeelID]() ContentMiddleware[KID] {
+	return func(ContentProviderCtx[KID]) ContentProviderCtx[KID] {
+		return func(_ context.Context, id KID, info FrameInfo) (string, error) {
+			return core.DebugContentProvider(id, info)
+		}
+	}
+}
+
+// DefaultMemoizeContentSize is the number of entries [MemoizeContent] keeps
+// when given a size below 1.
+const DefaultMemoizeContentSize = 64
+
+// MemoizeContent caches successful content by frame ID and [FrameInfo], so
+// the wrapped provider runs once per ID and allocation. It keeps at most size
+// entries and evicts the least recently used; sizes below 1 use
+// [DefaultMemoizeContentSize]. Use it for content that depends only on its
+// arguments; errors are not cached.
+func MemoizeContent[KID KeelID](size int) ContentMiddleware[KID] {
+	type key struct {
+		id   KID
+		info FrameInfo
+	}
+	type entry struct {
+		key     key
+		content string
+	}
+	if size < 1 {
+		size = DefaultMemoizeContentSize
+	}
+	var mu sync.Mutex
+	order := list.New()
+	cache := map[key]*list.Element{}
+	return func(next ContentProviderCtx[KID]) ContentProviderCtx[KID] {
+		return func(ctx context.Context, id KID, info FrameInfo) (string, error) {
+			k := key{id: id, info: info}
+			mu.Lock()
+			if elem, ok := cache[k]; ok {
+				order.MoveToFront(elem)
+				content := elem.Value.(entry).content
+				mu.Unlock()
+				return content, nil
+			}
+			mu.Unlock()
+			content, err := next(ctx, id, info)
+			if err != nil {
+				return content, err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if elem, ok := cache[k]; ok {
+				order.MoveToFront(elem)
+				return content, nil
+			}
+			cache[k] = order.PushFront(entry{key: k, content: content})
+			if order.Len() > size {
+				oldest := order.Back()
+				order.Remove(oldest)
+				delete(cache, oldest.Value.(entry).key)
+			}
+			return content, nil
+		}
+	}
+}
+
+// MemoizeStyle caches styles by frame ID. The cache is never invalidated, so
+// use it for styles that do not depend on frame state.
+func MemoizeStyle[KID KeelID]() StyleMiddleware[KID] {
+	var mu sync.Mutex
+	cache := map[KID]*gloss.Style{}
+	return func(next StyleProviderCtx[KID]) StyleProviderCtx[KID] {
+		return func(ctx context.Context, id KID) *gloss.Style {
+			mu.Lock()
+			style, ok := cache[id]
+			mu.Unlock()
+			if ok {
+				return style
+			}
+			style = next(ctx, id)
+			mu.Lock()
+			cache[id] = style
+			mu.Unlock()
+			return style
+		}
+	}
+}
+
+// TimeContent logs a provider.timing event at debug level for each call,
+// with the frame ID, the duration and any error returned. Events are logged
+// with the render context.
+func TimeContent[KID KeelID](logger *slog.Logger) ContentMiddleware[KID] {
+	return func(next ContentProviderCtx[KID]) ContentProviderCtx[KID] {
+		return func(ctx context.Context, id KID, info FrameInfo) (string, error) {
+			if logger == nil || !logger.Enabled(ctx, slog.LevelDebug) {
+				return next(ctx, id, info)
+			}
+			start := time.Now()
+			content, err := next(ctx, id, info)
+			attrs := []slog.Attr{
+				slog.Any("id", id),
+				slog.String("provider", "content"),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.Any("err", err))
+			}
+			logging.LogEventContext(ctx, logger, slog.LevelDebug, logging.EventProviderTiming, "", attrs...)
+			return content, err
+		}
+	}
+}
+
+// RecoverContent recovers panics in the wrapped provider and returns them as
+// a [ProviderPanicError], logged as a provider.panic event. It is the
+// middleware form of [Config.SetRecoverPanics].
+func RecoverContent[KID KeelID](logger *slog.Logger) ContentMiddleware[KID] {
+	return func(next ContentProviderCtx[KID]) ContentProviderCtx[KID] {
+		return func(ctx context.Context, id KID, info FrameInfo) (content string, err error) {
+			defer func() {
+				if value := recover(); value != nil {
+					content, err = "", providerPanic(ctx, logger, id, "content", value)
+				}
+			}()
+			return next(ctx, id, info)
+		}
+	}
+}
+
+// RecoverStyle recovers panics in the wrapped provider, logs them as a
+// provider.panic event and returns no style.
+func RecoverStyle[KID KeelID](logger *slog.Logger) StyleMiddleware[KID] {
+	return func(next StyleProviderCtx[KID]) StyleProviderCtx[KID] {
+		return func(ctx context.Context, id KID) (style *gloss.Style) {
+			defer func() {
+				if value := recover(); value != nil {
+					style = nil
+					_ = providerPanic(ctx, logger, id, "style", value)
+				}
+			}()
+			return next(ctx, id)
+		}
+	}
+}
+
+// PlaceholderContent replaces provider errors with a placeholder showing the
+// error in the content box, so a failing frame renders without failing the
+// render. Unlike [ErrorModePlaceholder], the error is not returned.
+func PlaceholderContent[KID KeelID]() ContentMiddleware[KID] {
+	return func(next ContentProviderCtx[KID]) ContentProviderCtx[KID] {
+		return func(ctx context.Context, id KID, info FrameInfo) (string, error) {
+			content, err := next(ctx, id, info)
+			if err != nil {
+				return placeholder(err, engine.Rect{Width: info.ContentWidth, Height: info.ContentHeight}), nil
+			}
+			return content, nil
+		}
+	}
+}
+
+// RouteContent sends frames with a routed ID to their own provider and every
+// other frame to the wrapped provider.
+func RouteContent[KID KeelID](routes map[KID]ContentProvider[KID]) ContentMiddleware[KID] {
+	return func(next ContentProviderCtx[KID]) ContentProviderCtx[KID] {
+		return func(ctx context.Context, id KID, info FrameInfo) (string, error) {
+			if provider, ok := routes[id]; ok && provider != nil {
+				return provider(id, info)
+			}
+			return next(ctx, id, info)
+		}
+	}
+}
+
+// RouteStyle sends frames with a routed ID to their own provider and every
+// other frame to the wrapped provider.
+func RouteStyle[KID KeelID](routes map[KID]StyleProvider[KID]) StyleMiddleware[KID] {
+	return func(next StyleProviderCtx[KID]) StyleProviderCtx[KID] {
+		return func(ctx context.Context, id KID) *gloss.Style {
+			if provider, ok := routes[id]; ok && provider != nil {
+				return provider(id)
+			}
+			return next(ctx, id)
+		}
+	}
+}
+
+// buildContentChain composes the renderer's content middleware around its
+// content provider and stores the result. A missing provider returns a
+// [ContentProviderMissingError] so middleware can still supply content, and
+// debug mode is checked on each call so [Config.SetDebug] applies without a
+// rebuild.
+func (r *Renderer[KID]) buildContentChain() {
+	var provider ContentProviderCtx[KID]
+	switch content := r.content; {
+	case r.contentCtx != nil:
+		provider = r.contentCtx
+	case content != nil:
+		provider = func(_ context.Context, id KID, info FrameInfo) (string, error) {
+			return content(id, info)
+		}
+	default:
+		provider = func(_ context.Context, id KID, _ FrameInfo) (string, error) {
+			return "", &ContentProviderMissingError{ID: id}
+		}
+	}
+	base, debugContent := provider, DebugContent[KID]()(provider)
+	provider = func(ctx context.Context, id KID, info FrameInfo) (string, error) {
+		if r.config.Debug() {
+			return debugContent(ctx, id, info)
+		}
+		return base(ctx, id, info)
+	}
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		provider = r.middleware[i](provider)
+	}
+	r.contentChain = provider
+}
+
+// buildStyleChain composes the renderer's style middleware around its
+// stateful or plain style provider and stores the result, or nil when there
+// is nothing to call.
+func (r *Renderer[KID]) buildStyleChain() {
+	var provider StyleProviderCtx[KID]
+	switch style, stateStyle := r.style, r.stateStyle; {
+	case stateStyle != nil:
+		provider = func(_ context.Context, id KID) *gloss.Style {
+			return stateStyle(id, r.State(id))
+		}
+	case style != nil:
+		provider = func(_ context.Context, id KID) *gloss.Style {
+			return style(id)
+		}
+	case len(r.styleMiddleware) == 0:
+		r.styleChain = nil
+		return
+	default:
+		provider = func(context.Context, KID) *gloss.Style { return nil }
+	}
+	for i := len(r.styleMiddleware) - 1; i >= 0; i-- {
+		provider = r.styleMiddleware[i](provider)
+	}
+	r.styleChain = provider
+}
+
+// providerPanic converts a recovered provider panic into a
+// [ProviderPanicError] and logs it with the stack.
+func providerPanic[KID KeelID](ctx context.Context, logger *slog.Logger, id KID, provider string, value any) error {
+	err := &ProviderPanicError{ID: id, Provider: provider, Value: value, Stack: debug.Stack()}
+	logging.LogEventContext(
+		ctx,
+		logger,
+		slog.LevelError,
+		logging.EventProviderPanic,
+		"",
+		slog.Any("id", id),
+		slog.String("provider", provider),
+		slog.Any("value", value),
+		slog.String("stack", string(err.Stack)),
+	)
+	return err
+}
@@ -0,0 +1,251 @@
+package keel
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/trippwill/keel/logging"
+)
+
+func TestUseOrder(t *testing.T) {
+	var calls []string
+	trace := func(name string) ContentMiddleware[string] {
+		return func(next ContentProviderCtx[string]) ContentProviderCtx[string] {
+			return func(ctx context.Context, id string, info FrameInfo) (string, error) {
+				calls = append(calls, name)
+				content, err := next(ctx, id, info)
+				return name + content, err
+			}
+		}
+	}
+	renderer := NewRenderer(Exact(Fixed(3), "a"), nil, func(id string, _ FrameInfo) (string, error) {
+		return "x", nil
+	})
+	renderer.Use(trace("1"), trace("2"))
+
+	out, err := renderer.Render(Size{Width: 3, Height: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "12x" {
+		t.Fatalf("expected first middleware outermost, got %q", out)
+	}
+	if strings.Join(calls, ",") != "1,2" {
+		t.Fatalf("unexpected call order %v", calls)
+	}
+}
+
+func TestUseWithDebug(t *testing.T) {
+	renderer := NewRenderer(Exact(Fixed(3), "a"), nil, func(id string, _ FrameInfo) (string, error) {
+		return "x", nil
+	})
+	var wrapped bool
+	renderer.Use(func(next ContentProviderCtx[string]) ContentProviderCtx[string] {
+		return func(ctx context.Context, id string, info FrameInfo) (string, error) {
+			wrapped = true
+			return next(ctx, id, info)
+		}
+	})
+	renderer.Config().SetDebug(true)
+
+	out, err := renderer.Render(Size{Width: 3, Height: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !wrapped || out == "x  " {
+		t.Fatalf("expected middleware around debug content, got %q", out)
+	}
+}
+
+func TestMemoizeContent(t *testing.T) {
+	calls := 0
+	renderer := NewRenderer(Exact(Fixed(3), "a"), nil, func(id string, _ FrameInfo) (string, error) {
+		calls++
+		return "x", nil
+	})
+	renderer.Use(MemoizeContent[string](0))
+
+	for range 3 {
+		if _, err := renderer.Render(Size{Width: 3, Height: 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected one provider call, got %d", calls)
+	}
+	if _, err := renderer.Render(Size{Width: 4, Height: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected a call for the new allocation, got %d", calls)
+	}
+}
+
+func TestMemoizeContentEvicts(t *testing.T) {
+	calls := map[string]int{}
+	provider := MemoizeContent[string](2)(func(_ context.Context, id string, _ FrameInfo) (string, error) {
+		calls[id]++
+		return id, nil
+	})
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "a", "c", "a", "b"} {
+		if _, err := provider(ctx, id, FrameInfo{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls["a"] != 1 || calls["b"] != 2 || calls["c"] != 1 {
+		t.Fatalf("expected least recently used entries to be evicted, got %v", calls)
+	}
+}
+
+func TestTimeContent(t *testing.T) {
+	handler, entries := newCaptureHandler()
+	errBoom := errors.New("boom")
+	provider := TimeContent[string](slog.New(handler))(func(context.Context, string, FrameInfo) (string, error) {
+		return "", errBoom
+	})
+
+	if _, err := provider(context.Background(), "a", FrameInfo{}); !errors.Is(err, errBoom) {
+		t.Fatalf("expected provider error, got %v", err)
+	}
+	if len(*entries) != 1 {
+		t.Fatalf("expected one entry, got %d", len(*entries))
+	}
+	entry := (*entries)[0]
+	if entry.attrs["event"] != string(logging.EventProviderTiming) || entry.attrs["id"] != "a" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+	if _, ok := entry.attrs["duration"]; !ok {
+		t.Fatalf("expected duration attr")
+	}
+	if _, ok := entry.attrs["err"]; !ok {
+		t.Fatalf("expected err attr")
+	}
+}
+
+func TestRecoverContent(t *testing.T) {
+	provider := RecoverContent[string](nil)(func(context.Context, string, FrameInfo) (string, error) {
+		panic("boom")
+	})
+	_, err := provider(context.Background(), "a", FrameInfo{})
+	var panicErr *ProviderPanicError
+	if !errors.As(err, &panicErr) || panicErr.ID != "a" || panicErr.Value != "boom" {
+		t.Fatalf("expected ProviderPanicError, got %v", err)
+	}
+}
+
+func TestRecoverStyle(t *testing.T) {
+	renderer := NewRenderer(Exact(Fixed(2), "a"), func(id string) *gloss.Style {
+		panic("boom")
+	}, func(id string, _ FrameInfo) (string, error) {
+		return "ok", nil
+	})
+	renderer.UseStyle(RecoverStyle[string](nil))
+
+	out, err := renderer.Render(Size{Width: 2, Height: 1})
+	if err != nil || out != "ok" {
+		t.Fatalf("expected unstyled frame, got %q, %v", out, err)
+	}
+}
+
+func TestPlaceholderContent(t *testing.T) {
+	renderer := NewRenderer(Exact(Fixed(4), "a"), nil, func(id string, _ FrameInfo) (string, error) {
+		return "", errors.New("bad")
+	})
+	renderer.Use(PlaceholderContent[string]())
+
+	out, err := renderer.Render(Size{Width: 4, Height: 1})
+	if err != nil {
+		t.Fatalf("expected error to be replaced, got %v", err)
+	}
+	if !strings.Contains(out, "! b") {
+		t.Fatalf("expected placeholder, got %q", out)
+	}
+}
+
+func TestRouteContentAndStyle(t *testing.T) {
+	layout := Row(FlexUnit(),
+		Exact(Fixed(3), "a"),
+		Exact(Fixed(3), "b"),
+	)
+	renderer := NewRenderer[string](layout, nil, nil)
+	renderer.Use(RouteContent(map[string]ContentProvider[string]{
+		"a": func(string, FrameInfo) (string, error) { return "a", nil },
+	}))
+	padded := gloss.NewStyle().PaddingLeft(1)
+	renderer.UseStyle(RouteStyle(map[string]StyleProvider[string]{
+		"a": func(string) *gloss.Style { return &padded },
+	}))
+
+	_, err := renderer.Render(Size{Width: 6, Height: 1})
+	var missing *ContentProviderMissingError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected missing provider for unrouted frame, got %v", err)
+	}
+
+	renderer.Use(RouteContent(map[string]ContentProvider[string]{
+		"b": func(string, FrameInfo) (string, error) { return "b", nil },
+	}))
+	out, err := renderer.Render(Size{Width: 6, Height: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != " a b  " {
+		t.Fatalf("unexpected output %q", out)
+	}
+}
+
+func TestMemoizeStyle(t *testing.T) {
+	calls := 0
+	style := gloss.NewStyle()
+	provider := MemoizeStyle[string]()(func(context.Context, string) *gloss.Style {
+		calls++
+		return &style
+	})
+	ctx := context.Background()
+	provider(ctx, "a")
+	provider(ctx, "a")
+	provider(ctx, "b")
+	if calls != 2 {
+		t.Fatalf("expected one call per ID, got %d", calls)
+	}
+}
+
+func TestMiddlewareLogsWithRenderContext(t *testing.T) {
+	handler := &contextHandler{}
+	logger := slog.New(handler)
+	layout := Row(FlexUnit(),
+		Exact(Fixed(2), "a"),
+		Exact(Fixed(2), "b"),
+	)
+	renderer := NewRenderer(layout, func(id string) *gloss.Style {
+		if id == "a" {
+			panic("bad style")
+		}
+		return nil
+	}, func(id string, _ FrameInfo) (string, error) {
+		if id == "b" {
+			panic("bad content")
+		}
+		return "ok", nil
+	})
+	renderer.Use(TimeContent[string](logger), RecoverContent[string](logger))
+	renderer.UseStyle(RecoverStyle[string](logger))
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace-1")
+
+	if _, err := renderer.RenderContext(ctx, Size{Width: 4, Height: 1}); !errors.Is(err, ErrProviderPanic) {
+		t.Fatalf("expected recovered panic, got %v", err)
+	}
+	if len(handler.traces) < 3 {
+		t.Fatalf("expected timing and panic records, got %v", handler.traces)
+	}
+	for _, trace := range handler.traces {
+		if trace != "trace-1" {
+			t.Fatalf("expected render context in every record, got %v", handler.traces)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package keel
+
+import (
+	"context"
+	"errors"
+
+	"github.com/trippwill/keel/core"
+	"github.com/trippwill/keel/engine"
+)
+
+// maxMinSizeExtent bounds the search along each axis in [MinSize].
+const maxMinSizeExtent = 1 << 14
+
+// MinSizeResult describes the smallest size at which a spec renders.
+type MinSizeResult struct {
+	Size Size
+	// WidthBinding and HeightBinding describe the constraint that fails when
+	// the corresponding axis shrinks by one cell. They are nil when the axis
+	// is already zero or shrinking fails for a reason other than size.
+	WidthBinding, HeightBinding *ExtentTooSmallError
+}
+
+// MinSize computes the smallest [Size] at which [Renderer.Render] succeeds for
+// the given spec and providers with a fresh config.
+//
+// The search renders the spec, growing each axis by the shortfall reported in
+// [ExtentTooSmallError] until rendering succeeds, then shrinks each axis to the
+// smallest size that still renders. The shrink is a binary search, so it
+// assumes success is monotonic along each axis: if the spec renders at some
+// width (or height), it renders at every larger one. Specs that render at a
+// size but fail at a larger one, for example through content that changes with
+// its allocation, may get a size that is not the smallest. Fixed extents,
+// MinCells, style frame sizes and measured content (for [Exact] and
+// [WrapStrict] frames) are all accounted for because they are checked by the
+// render pass itself.
+//
+// Content providers are invoked at every candidate size. Errors other than
+// [ExtentTooSmallError] are returned as-is; if no size up to 16384 cells on an
+// axis renders, the last size error is returned.
+func MinSize[KID KeelID](spec Spec, style StyleProvider[KID], content ContentProvider[KID]) (MinSizeResult, error) {
+	return MinSizeWithConfig(NewConfig(), spec, style, content)
+}
+
+// MinSizeWithConfig is [MinSize] rendering with the provided config, so its
+// dimension check, error mode and logger apply to every candidate size.
+func MinSizeWithConfig[KID KeelID](config *Config, spec Spec, style StyleProvider[KID], content ContentProvider[KID]) (MinSizeResult, error) {
+	if spec == nil {
+		return MinSizeResult{}, ErrSpecMissing
+	}
+	renderer := NewRendererWithConfig(config, spec, style, content)
+	arranger := engine.NewArranger[KID](renderer.config.logger)
+	ctx := context.Background()
+	render := func(size Size) error {
+		layout, err := arranger.ArrangeContext(ctx, spec, size)
+		if err != nil {
+			return convertError(err)
+		}
+		_, err = renderer.renderLayout(ctx, layout)
+		return err
+	}
+
+	size := Size{}
+	for {
+		err := render(size)
+		if err == nil {
+			break
+		}
+		var tooSmall *ExtentTooSmallError
+		if !errors.As(err, &tooSmall) {
+			return MinSizeResult{}, err
+		}
+		step := max(tooSmall.Need-tooSmall.Have, 1)
+		if tooSmall.Axis == core.AxisVertical.String() {
+			size.Height += step
+		} else {
+			size.Width += step
+		}
+		if size.Width > maxMinSizeExtent || size.Height > maxMinSizeExtent {
+			return MinSizeResult{}, err
+		}
+	}
+
+	size.Width = shrinkAxis(size.Width, func(n int) bool {
+		return render(Size{Width: n, Height: size.Height}) == nil
+	})
+	size.Height = shrinkAxis(size.Height, func(n int) bool {
+		return render(Size{Width: size.Width, Height: n}) == nil
+	})
+
+	result := MinSizeResult{Size: size}
+	if size.Width > 0 {
+		result.WidthBinding = tooSmallFrom(render(Size{Width: size.Width - 1, Height: size.Height}))
+	}
+	if size.Height > 0 {
+		result.HeightBinding = tooSmallFrom(render(Size{Width: size.Width, Height: size.Height - 1}))
+	}
+	return result, nil
+}
+
+// shrinkAxis returns the smallest n in [0, hi] for which ok reports true,
+// assuming ok(hi) holds and success is monotonic along the axis.
+func shrinkAxis(hi int, ok func(n int) bool) int {
+	lo := 0
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if ok(mid) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return hi
+}
+
+func tooSmallFrom(err error) *ExtentTooSmallError {
+	var tooSmall *ExtentTooSmallError
+	if errors.As(err, &tooSmall) {
+		return tooSmall
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package keel
+
+import (
+	"errors"
+	"testing"
+
+	gloss "github.com/charmbracelet/lipgloss"
+)
+
+func TestMinSizeAccountsForExtentsStyleAndContent(t *testing.T) {
+	layout := Col(FlexUnit(),
+		Exact(Fixed(1), "title"),
+		Row(FlexUnit(),
+			Exact(Fixed(3), "nav"),
+			Exact(FlexMin(1, 2), "body"),
+		),
+	)
+	border := gloss.NewStyle().Border(gloss.NormalBorder())
+	style := func(id string) *gloss.Style {
+		if id == "body" {
+			return &border
+		}
+		return nil
+	}
+	content := func(id string, _ FrameInfo) (string, error) {
+		switch id {
+		case "title":
+			return "title", nil
+		case "body":
+			return "hello\nworld", nil
+		default:
+			return "", nil
+		}
+	}
+
+	got, err := MinSize(layout, style, content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Size{Width: 3 + 5 + 2, Height: 1 + 2 + 2}
+	if got.Size != want {
+		t.Fatalf("expected %+v, got %+v", want, got.Size)
+	}
+
+	renderer := NewRenderer(layout, style, content)
+	if _, err := renderer.Render(got.Size); err != nil {
+		t.Fatalf("expected render at min size to succeed: %v", err)
+	}
+	if got.WidthBinding == nil || got.WidthBinding.ID != "body" {
+		t.Fatalf("expected body to bind width, got %+v", got.WidthBinding)
+	}
+	if got.HeightBinding == nil || got.HeightBinding.ID != "body" || got.HeightBinding.Path != "/1/1" {
+		t.Fatalf("expected body at /1/1 to bind height, got %+v", got.HeightBinding)
+	}
+}
+
+func TestMinSizeFlexShare(t *testing.T) {
+	layout := Row(FlexUnit(),
+		Exact(FlexUnit(), "a"),
+		Exact(FlexUnit(), "b"),
+	)
+	content := func(id string, _ FrameInfo) (string, error) {
+		if id == "b" {
+			return "abcd", nil
+		}
+		return "", nil
+	}
+
+	got, err := MinSize(layout, nil, content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Size{Width: 8, Height: 1}
+	if got.Size != want {
+		t.Fatalf("expected %+v, got %+v", want, got.Size)
+	}
+	if got.WidthBinding == nil || got.WidthBinding.ID != "b" || got.WidthBinding.Reason != "content" {
+		t.Fatalf("expected content of b to bind width, got %+v", got.WidthBinding)
+	}
+}
+
+func TestMinSizeEmptyContent(t *testing.T) {
+	got, err := MinSize(Exact(FlexUnit(), "a"), nil, makeContentProvider(""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Empty content still measures one line tall.
+	want := Size{Width: 0, Height: 1}
+	if got.Size != want {
+		t.Fatalf("expected %+v, got %+v", want, got.Size)
+	}
+	if got.WidthBinding != nil {
+		t.Fatalf("expected no width binding, got %+v", got.WidthBinding)
+	}
+	if got.HeightBinding == nil || got.HeightBinding.Reason != "content" {
+		t.Fatalf("expected content to bind height, got %+v", got.HeightBinding)
+	}
+}
+
+func TestMinSizeUnsatisfiable(t *testing.T) {
+	layout := Row(FlexUnit(),
+		Exact(Fixed(1), "a"),
+		Exact(FlexUnit(), "b"),
+	)
+	style := func(id string) *gloss.Style {
+		s := gloss.NewStyle().Border(gloss.NormalBorder())
+		return &s
+	}
+
+	_, err := MinSize(layout, style, makeContentProvider(""))
+	var tooSmall *ExtentTooSmallError
+	if !errors.As(err, &tooSmall) {
+		t.Fatalf("expected ExtentTooSmallError, got %v", err)
+	}
+	if tooSmall.ID != "a" {
+		t.Fatalf("expected frame a, got %+v", tooSmall)
+	}
+}
+
+func TestMinSizeSpecError(t *testing.T) {
+	_, err := MinSize[string](Row(FlexUnit(), nil), nil, nil)
+	if !errors.Is(err, ErrConfigurationInvalid) {
+		t.Fatalf("expected ErrConfigurationInvalid, got %v", err)
+	}
+}
+
+func TestMinSizeWithConfigDimensionCheck(t *testing.T) {
+	layout := Row(FlexUnit(),
+		Exact(Fixed(1), "a"),
+		Overflow(FlexUnit(), "b"),
+	)
+	content := func(id string, _ FrameInfo) (string, error) {
+		if id == "b" {
+			return "abcd", nil
+		}
+		return "a", nil
+	}
+
+	if _, err := MinSize(layout, nil, content); err != nil {
+		t.Fatalf("unexpected error without dimension check: %v", err)
+	}
+
+	config := NewConfig()
+	config.SetDimensionCheck(DimensionCheckStrict)
+	_, err := MinSizeWithConfig(config, layout, nil, content)
+	var mismatch *DimensionMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected DimensionMismatchError, got %v", err)
+	}
+}
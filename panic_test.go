@@ -0,0 +1,114 @@
+package keel
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/trippwill/keel/logging"
+)
+
+func panickingRenderer() *Renderer[string] {
+	layout := Row(FlexUnit(),
+		Exact(Fixed(3), "a"),
+		Exact(Fixed(3), "b"),
+	)
+	return NewRenderer(layout, nil, func(id string, _ FrameInfo) (string, error) {
+		if id == "b" {
+			panic("boom")
+		}
+		return "ok", nil
+	})
+}
+
+func TestRecoverContentPanic(t *testing.T) {
+	renderer := panickingRenderer()
+	renderer.Config().SetRecoverPanics(true)
+	handler, entries := newCaptureHandler()
+	renderer.Config().SetLogger(slog.New(handler))
+
+	_, err := renderer.Render(Size{Width: 6, Height: 1})
+	var panicErr *ProviderPanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected ProviderPanicError, got %v", err)
+	}
+	if !errors.Is(err, ErrProviderPanic) {
+		t.Fatalf("expected ErrProviderPanic, got %v", err)
+	}
+	if panicErr.ID != "b" || panicErr.Provider != "content" || panicErr.Value != "boom" {
+		t.Fatalf("unexpected panic error: %+v", panicErr)
+	}
+	if !strings.Contains(string(panicErr.Stack), "panickingRenderer") {
+		t.Fatalf("expected stack to include the provider, got %s", panicErr.Stack)
+	}
+
+	var panics []logEntry
+	for _, entry := range *entries {
+		if entry.attrs["event"] == string(logging.EventProviderPanic) {
+			panics = append(panics, entry)
+		}
+	}
+	if len(panics) != 1 {
+		t.Fatalf("expected one provider.panic event, got %d", len(panics))
+	}
+	if panics[0].level != slog.LevelError || panics[0].attrs["provider"] != "content" {
+		t.Fatalf("unexpected provider.panic entry: %+v", panics[0])
+	}
+}
+
+func TestRecoverStylePanic(t *testing.T) {
+	errStyle := errors.New("bad style")
+	renderer := NewRenderer(Exact(Fixed(2), "a"), func(id string) *gloss.Style {
+		panic(errStyle)
+	}, func(id string, _ FrameInfo) (string, error) {
+		return "ok", nil
+	})
+	renderer.Config().SetRecoverPanics(true)
+
+	_, err := renderer.Render(Size{Width: 2, Height: 1})
+	var panicErr *ProviderPanicError
+	if !errors.As(err, &panicErr) || panicErr.Provider != "style" {
+		t.Fatalf("expected style ProviderPanicError, got %v", err)
+	}
+	if !errors.Is(err, errStyle) {
+		t.Fatalf("expected panic value to be wrapped, got %v", err)
+	}
+}
+
+func TestRecoverPanicPlaceholder(t *testing.T) {
+	renderer := panickingRenderer()
+	renderer.Config().SetRecoverPanics(true)
+	renderer.Config().SetErrorMode(ErrorModePlaceholder)
+	renderer.Config().SetConcurrency(2)
+
+	out, err := renderer.Render(Size{Width: 6, Height: 1})
+	if !errors.Is(err, ErrProviderPanic) {
+		t.Fatalf("expected ErrProviderPanic, got %v", err)
+	}
+	if !strings.HasPrefix(out, "ok ") {
+		t.Fatalf("expected healthy frame to render, got %q", out)
+	}
+}
+
+func TestPanicPropagatesByDefault(t *testing.T) {
+	renderer := panickingRenderer()
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic to propagate")
+		}
+	}()
+	_, _ = renderer.Render(Size{Width: 6, Height: 1})
+}
+
+func TestPanicPropagatesFromWorkers(t *testing.T) {
+	renderer := panickingRenderer()
+	renderer.Config().SetConcurrency(2)
+	defer func() {
+		if value := recover(); value != "boom" {
+			t.Fatalf("expected worker panic on the calling goroutine, got %v", value)
+		}
+	}()
+	_, _ = renderer.Render(Size{Width: 6, Height: 1})
+}
@@ -0,0 +1,67 @@
+package keel
+
+import (
+	"context"
+
+	"github.com/trippwill/keel/engine"
+)
+
+// RenderFrame renders the first frame with the given ID from the layout
+// cached by the last render, and returns its output and its rect in the full
+// layout. Use it to repaint one region, for example by positioning the cursor
+// at the rect and writing the output line by line. When the renderer serves
+// several sizes, the layout is the one of whichever size rendered last.
+//
+// It fails with [ErrLayoutMissing] before the first render, and after an edit
+// bumps the generation of a versioned spec until the next render. It fails
+// with an [UnknownFrameIDError] when no frame has the ID.
+func (r *Renderer[KID]) RenderFrame(id KID) (string, engine.Rect, error) {
+	if r == nil {
+		return "", engine.Rect{}, ErrRendererMissing
+	}
+	layout, ok := r.lastLayout()
+	if !ok {
+		return "", engine.Rect{}, ErrLayoutMissing
+	}
+	node, ok := layout.Frame(id)
+	if !ok {
+		return "", engine.Rect{}, &UnknownFrameIDError{ID: id}
+	}
+	return r.renderNode(context.Background(), node)
+}
+
+// RenderPath renders the frame or stack at a slash-delimited slot path (see
+// [engine.LayoutNode]) from the layout cached by the last render, and returns
+// its output and its rect in the full layout, with the size caveat of
+// [Renderer.RenderFrame].
+//
+// It fails with [ErrLayoutMissing] before the first render or after an edit,
+// as RenderFrame does, and with an [UnknownPathError] when no node has the
+// path.
+func (r *Renderer[KID]) RenderPath(path string) (string, engine.Rect, error) {
+	if r == nil {
+		return "", engine.Rect{}, ErrRendererMissing
+	}
+	layout, ok := r.lastLayout()
+	if !ok {
+		return "", engine.Rect{}, ErrLayoutMissing
+	}
+	node, ok := layout.Node(path)
+	if !ok {
+		return "", engine.Rect{}, &UnknownPathError{Path: path}
+	}
+	return r.renderNode(context.Background(), node)
+}
+
+// renderNode renders an arranged node on its own, as if its rect were the
+// whole layout.
+func (r *Renderer[KID]) renderNode(ctx context.Context, node engine.LayoutNode[KID]) (string, engine.Rect, error) {
+	rect := node.Rect
+	layout := engine.Layout[KID]{
+		Width:  rect.Width,
+		Height: rect.Height,
+		Root:   node.Translate(-rect.X, -rect.Y),
+	}
+	out, err := r.renderLayout(ctx, layout)
+	return out, rect, err
+}
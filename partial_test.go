@@ -0,0 +1,125 @@
+package keel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/trippwill/keel/engine"
+)
+
+func partialRenderer() *Renderer[string] {
+	layout := Col(FlexUnit(),
+		Exact(Fixed(1), "title"),
+		Row(FlexUnit(),
+			Wrap(Fixed(4), "nav"),
+			Clip(FlexUnit(), "clock"),
+		),
+	)
+	return NewRenderer(layout, nil, func(id string, _ FrameInfo) (string, error) {
+		switch id {
+		case "title":
+			return "Title", nil
+		case "nav":
+			return "a b c", nil
+		case "clock":
+			return "12:00", nil
+		}
+		return "", &UnknownFrameIDError{ID: id}
+	})
+}
+
+func region(out string, rect engine.Rect) string {
+	lines := strings.Split(out, "\n")[rect.Y : rect.Y+rect.Height]
+	for i, line := range lines {
+		lines[i] = line[rect.X : rect.X+rect.Width]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestRenderFrameAndPath(t *testing.T) {
+	renderer := partialRenderer()
+	if _, _, err := renderer.RenderFrame("clock"); !errors.Is(err, ErrLayoutMissing) {
+		t.Fatalf("expected ErrLayoutMissing before the first render, got %v", err)
+	}
+	full, err := renderer.Render(Size{Width: 10, Height: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, rect, err := renderer.RenderFrame("clock")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rect != (engine.Rect{X: 4, Y: 1, Width: 6, Height: 2}) {
+		t.Fatalf("unexpected rect %+v", rect)
+	}
+	if want := region(full, rect); out != want {
+		t.Fatalf("expected %q, got %q", want, out)
+	}
+
+	out, rect, err = renderer.RenderPath("/1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rect != (engine.Rect{X: 0, Y: 1, Width: 10, Height: 2}) {
+		t.Fatalf("unexpected rect %+v", rect)
+	}
+	if want := region(full, rect); out != want {
+		t.Fatalf("expected %q, got %q", want, out)
+	}
+
+	if out, _, err := renderer.RenderPath("/"); err != nil || out != full {
+		t.Fatalf("expected root path to match Render, got %q, %v", out, err)
+	}
+	if _, _, err := renderer.RenderFrame("missing"); !errors.Is(err, ErrUnknownFrameID) {
+		t.Fatalf("expected ErrUnknownFrameID, got %v", err)
+	}
+	if _, _, err := renderer.RenderPath("/3"); !errors.Is(err, ErrUnknownPath) {
+		t.Fatalf("expected ErrUnknownPath, got %v", err)
+	}
+}
+
+func TestLayoutReadersFollowSpecEdits(t *testing.T) {
+	row := MutableRow(FlexUnit(),
+		Exact(Fixed(2), "a"),
+		Exact(Fixed(2), "b"),
+	)
+	renderer := NewRenderer[string](row, nil, func(id string, _ FrameInfo) (string, error) {
+		return id, nil
+	})
+	router := NewRouter(renderer)
+	var routed int
+	router.Handle("a", func(MouseEvent) { routed++ })
+	size := Size{Width: 4, Height: 1}
+	if _, err := renderer.Render(size); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	renderer.Focus().Set("a")
+
+	if _, err := row.Remove(0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := renderer.RenderFrame("a"); !errors.Is(err, ErrLayoutMissing) {
+		t.Fatalf("expected ErrLayoutMissing after an edit, got %v", err)
+	}
+	if _, _, err := renderer.RenderPath("/1"); !errors.Is(err, ErrLayoutMissing) {
+		t.Fatalf("expected ErrLayoutMissing after an edit, got %v", err)
+	}
+	if _, ok := renderer.ContentAt(0, 0); ok {
+		t.Fatalf("expected no hit after an edit")
+	}
+	if router.Dispatch(MouseEvent{Button: MouseButtonLeft}) || routed != 0 {
+		t.Fatalf("expected no dispatch after an edit")
+	}
+	if id, _ := renderer.Focus().Move(DirectionRight); id != "a" {
+		t.Fatalf("expected focus to stay on a, got %q", id)
+	}
+
+	if _, err := renderer.Render(size); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hit, ok := renderer.ContentAt(0, 0); !ok || hit.ID != "b" {
+		t.Fatalf("expected b after re-rendering, got %+v, %v", hit, ok)
+	}
+}
@@ -0,0 +1,54 @@
+package keel
+
+import (
+	"strings"
+
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/trippwill/keel/engine"
+)
+
+var placeholderStyle = gloss.NewStyle().Foreground(gloss.Color("9"))
+
+// placeholder renders a failed frame as a red box with a "!" and the error
+// message, wrapped and truncated to the rect. Rects smaller than 3x3 show a
+// single truncated line without a border.
+func placeholder(err error, rect engine.Rect) string {
+	width, height := rect.Width, rect.Height
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	msg := "! " + err.Error()
+	if width < 3 || height < 3 {
+		lines := make([]string, height)
+		lines[0] = placeholderStyle.Render(fitLine(ansi.Truncate(msg, width, "…"), width))
+		for i := 1; i < height; i++ {
+			lines[i] = strings.Repeat(" ", width)
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	innerWidth, innerHeight := width-2, height-2
+	box := placeholderStyle.
+		Border(gloss.NormalBorder()).
+		BorderForeground(placeholderStyle.GetForeground()).
+		Width(innerWidth).
+		Height(innerHeight)
+	if innerHeight == 1 {
+		return box.Render(ansi.Truncate(msg, innerWidth, "…"))
+	}
+	body := strings.Split(ansi.Wordwrap(msg, innerWidth, ""), "\n")
+	for i, line := range body {
+		body[i] = ansi.Hardwrap(line, innerWidth, true)
+	}
+	body = strings.Split(strings.Join(body, "\n"), "\n")
+	if len(body) > innerHeight {
+		body = body[:innerHeight]
+		last := body[innerHeight-1]
+		if ansi.StringWidth(last) >= innerWidth {
+			last = ansi.Truncate(last, innerWidth-1, "")
+		}
+		body[innerHeight-1] = last + "…"
+	}
+	return box.Render(strings.Join(body, "\n"))
+}
@@ -0,0 +1,106 @@
+package keel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/trippwill/keel/engine"
+)
+
+func tolerantRenderer() (*Renderer[string], error) {
+	errWidget := errors.New("widget broke")
+	layout := Row(FlexUnit(),
+		Exact(Fixed(8), "a"),
+		Exact(Fixed(3), "b"),
+		Exact(FlexUnit(), "c"),
+	)
+	renderer := NewRenderer(layout, nil, func(id string, _ FrameInfo) (string, error) {
+		switch id {
+		case "a":
+			return "", errWidget
+		case "c":
+			return "far too wide", nil
+		}
+		return "ok", nil
+	})
+	renderer.Config().SetErrorMode(ErrorModePlaceholder)
+	return renderer, errWidget
+}
+
+func TestRenderPlaceholderMode(t *testing.T) {
+	renderer, errWidget := tolerantRenderer()
+	size := Size{Width: 16, Height: 3}
+
+	out, err := renderer.Render(size)
+	if err == nil {
+		t.Fatalf("expected joined frame errors")
+	}
+	if !errors.Is(err, errWidget) || !errors.Is(err, ErrExtentTooSmall) {
+		t.Fatalf("expected provider and extent errors, got %v", err)
+	}
+	var tooSmall *ExtentTooSmallError
+	if !errors.As(err, &tooSmall) || tooSmall.ID != "c" || tooSmall.Path != "/2" {
+		t.Fatalf("expected converted extent error for c, got %v", err)
+	}
+	if got := strings.Count(err.Error(), "\n"); got != 1 {
+		t.Fatalf("expected 2 errors in tree order, got %q", err.Error())
+	}
+	if !strings.HasPrefix(err.Error(), "widget broke") {
+		t.Fatalf("expected errors in tree order, got %q", err.Error())
+	}
+
+	want := strings.Join([]string{
+		"┌──────┐ok ┌───┐",
+		"│! wid…│   │! …│",
+		"└──────┘   └───┘",
+	}, "\n")
+	if out != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, out)
+	}
+
+	renderer.Config().SetConcurrency(3)
+	got, concurrentErr := renderer.Render(size)
+	if got != out || concurrentErr.Error() != err.Error() {
+		t.Fatalf("expected concurrent render to match, got %q, %v", got, concurrentErr)
+	}
+}
+
+func TestRenderPlaceholderModeDiff(t *testing.T) {
+	renderer, errWidget := tolerantRenderer()
+	var b strings.Builder
+	err := renderer.RenderDiff(Size{Width: 16, Height: 3}, &b)
+	if !errors.Is(err, errWidget) {
+		t.Fatalf("expected frame errors, got %v", err)
+	}
+	if !strings.Contains(b.String(), "ok") || renderer.previous == nil {
+		t.Fatalf("expected partial frame written, got %q", b.String())
+	}
+}
+
+func TestRenderAbortModeIsDefault(t *testing.T) {
+	renderer, errWidget := tolerantRenderer()
+	renderer.Config().SetErrorMode(ErrorModeAbort)
+	out, err := renderer.Render(Size{Width: 16, Height: 3})
+	if out != "" || !errors.Is(err, errWidget) || errors.Is(err, ErrExtentTooSmall) {
+		t.Fatalf("expected first error only, got %q, %v", out, err)
+	}
+}
+
+func TestPlaceholder(t *testing.T) {
+	err := errors.New("boom went the widget")
+	cases := []struct {
+		rect engine.Rect
+		want string
+	}{
+		{engine.Rect{Width: 12, Height: 4}, "┌──────────┐\n│! boom    │\n│went the… │\n└──────────┘"},
+		{engine.Rect{Width: 6, Height: 1}, "! boo…"},
+		{engine.Rect{Width: 2, Height: 2}, "!…\n  "},
+		{engine.Rect{Width: 0, Height: 2}, ""},
+	}
+	for _, tc := range cases {
+		if got := placeholder(err, tc.rect); got != tc.want {
+			t.Fatalf("rect %+v: expected %q, got %q", tc.rect, tc.want, got)
+		}
+	}
+}
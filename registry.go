@@ -0,0 +1,127 @@
+package keel
+
+import (
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/trippwill/keel/core"
+)
+
+// Registry maps frame IDs to individual content and style providers, in
+// place of a single provider with a switch over IDs. [Registry.Check] reports
+// IDs that a spec uses but the registry does not cover, and the reverse.
+type Registry[KID KeelID] struct {
+	content map[KID]ContentProvider[KID]
+	style   map[KID]StyleProvider[KID]
+	order   []KID
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry[KID KeelID]() *Registry[KID] {
+	return &Registry[KID]{
+		content: map[KID]ContentProvider[KID]{},
+		style:   map[KID]StyleProvider[KID]{},
+	}
+}
+
+// Content registers the content provider for id, replacing any previous one,
+// and returns the registry for chaining.
+func (r *Registry[KID]) Content(id KID, p ContentProvider[KID]) *Registry[KID] {
+	if r == nil {
+		return nil
+	}
+	r.track(id)
+	r.content[id] = p
+	return r
+}
+
+// Style registers the style provider for id, replacing any previous one, and
+// returns the registry for chaining.
+func (r *Registry[KID]) Style(id KID, p StyleProvider[KID]) *Registry[KID] {
+	if r == nil {
+		return nil
+	}
+	r.track(id)
+	r.style[id] = p
+	return r
+}
+
+// ContentProvider returns a provider that dispatches to the registered
+// providers. Unregistered IDs fail with an [UnknownFrameIDError].
+func (r *Registry[KID]) ContentProvider() ContentProvider[KID] {
+	return func(id KID, info FrameInfo) (string, error) {
+		if r != nil {
+			if p := r.content[id]; p != nil {
+				return p(id, info)
+			}
+		}
+		return "", &UnknownFrameIDError{ID: id}
+	}
+}
+
+// StyleProvider returns a provider that dispatches to the registered
+// providers. Unregistered IDs have no style.
+func (r *Registry[KID]) StyleProvider() StyleProvider[KID] {
+	return func(id KID) *gloss.Style {
+		if r != nil {
+			if p := r.style[id]; p != nil {
+				return p(id)
+			}
+		}
+		return nil
+	}
+}
+
+// Check verifies the registry against the frames in spec. Every frame needs
+// a content provider; styles are optional. It returns a [RegistryError]
+// listing frame IDs without content in document order and registered IDs
+// that no frame uses in registration order, or nil when both are empty.
+func (r *Registry[KID]) Check(spec Spec) error {
+	used := map[KID]bool{}
+	var missing []any
+	walkFrames(spec, func(frame core.FrameSpec[KID]) {
+		id := frame.ID()
+		if used[id] {
+			return
+		}
+		used[id] = true
+		if r == nil || r.content[id] == nil {
+			missing = append(missing, id)
+		}
+	})
+	var unused []any
+	if r != nil {
+		for _, id := range r.order {
+			if !used[id] {
+				unused = append(unused, id)
+			}
+		}
+	}
+	if len(missing) == 0 && len(unused) == 0 {
+		return nil
+	}
+	return &RegistryError{Missing: missing, Unused: unused}
+}
+
+func (r *Registry[KID]) track(id KID) {
+	if _, ok := r.content[id]; ok {
+		return
+	}
+	if _, ok := r.style[id]; ok {
+		return
+	}
+	r.order = append(r.order, id)
+}
+
+// NewRendererWithRegistry returns a renderer for spec with a fresh config that
+// takes its content and style providers from registry, and checks the registry
+// against spec. The renderer is returned even when the check fails, so callers
+// can treat a [RegistryError] as a warning.
+func NewRendererWithRegistry[KID KeelID](spec Spec, registry *Registry[KID]) (*Renderer[KID], error) {
+	return NewRendererWithRegistryConfig(NewConfig(), spec, registry)
+}
+
+// NewRendererWithRegistryConfig is [NewRendererWithRegistry] using the
+// provided config.
+func NewRendererWithRegistryConfig[KID KeelID](config *Config, spec Spec, registry *Registry[KID]) (*Renderer[KID], error) {
+	renderer := NewRendererWithConfig(config, spec, registry.StyleProvider(), registry.ContentProvider())
+	return renderer, registry.Check(spec)
+}
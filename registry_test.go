@@ -0,0 +1,95 @@
+package keel
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	gloss "github.com/charmbracelet/lipgloss"
+)
+
+func TestRegistryProviders(t *testing.T) {
+	style := gloss.NewStyle().PaddingLeft(1)
+	registry := NewRegistry[string]().
+		Content("a", func(string, FrameInfo) (string, error) { return "a", nil }).
+		Style("a", func(string) *gloss.Style { return &style })
+
+	content := registry.ContentProvider()
+	if out, err := content("a", FrameInfo{}); err != nil || out != "a" {
+		t.Fatalf("expected registered content, got %q, %v", out, err)
+	}
+	if _, err := content("b", FrameInfo{}); !errors.Is(err, ErrUnknownFrameID) {
+		t.Fatalf("expected ErrUnknownFrameID, got %v", err)
+	}
+	styles := registry.StyleProvider()
+	if styles("a") != &style || styles("b") != nil {
+		t.Fatalf("unexpected styles")
+	}
+}
+
+func TestRegistryCheck(t *testing.T) {
+	layout := Row(FlexUnit(),
+		Exact(Fixed(1), "a"),
+		Col(FlexUnit(),
+			Exact(Fixed(1), "b"),
+			Exact(Fixed(1), "c"),
+		),
+		Exact(Fixed(1), "b"),
+	)
+	provider := func(string, FrameInfo) (string, error) { return "x", nil }
+	registry := NewRegistry[string]().
+		Style("z", nil).
+		Content("b", provider).
+		Style("b", nil).
+		Content("y", provider)
+
+	err := registry.Check(layout)
+	var registryErr *RegistryError
+	if !errors.As(err, &registryErr) || !errors.Is(err, ErrRegistryIncomplete) {
+		t.Fatalf("expected RegistryError, got %v", err)
+	}
+	if want := []any{"a", "c"}; !reflect.DeepEqual(registryErr.Missing, want) {
+		t.Fatalf("expected missing %v, got %v", want, registryErr.Missing)
+	}
+	if want := []any{"z", "y"}; !reflect.DeepEqual(registryErr.Unused, want) {
+		t.Fatalf("expected unused %v, got %v", want, registryErr.Unused)
+	}
+
+	registry = NewRegistry[string]().Content("a", provider).Content("b", provider).Content("c", provider)
+	if err := registry.Check(layout); err != nil {
+		t.Fatalf("expected complete registry, got %v", err)
+	}
+}
+
+func TestNewRendererWithRegistry(t *testing.T) {
+	registry := NewRegistry[string]().
+		Content("a", func(string, FrameInfo) (string, error) { return "ok", nil })
+	renderer, err := NewRendererWithRegistry(Row(FlexUnit(),
+		Exact(Fixed(2), "a"),
+		Exact(Fixed(2), "b"),
+	), registry)
+	if renderer == nil {
+		t.Fatalf("expected renderer")
+	}
+	var registryErr *RegistryError
+	if !errors.As(err, &registryErr) || len(registryErr.Missing) != 1 {
+		t.Fatalf("expected missing b, got %v", err)
+	}
+	if _, err := renderer.Render(Size{Width: 4, Height: 1}); !errors.Is(err, ErrUnknownFrameID) {
+		t.Fatalf("expected unknown frame at render, got %v", err)
+	}
+}
+
+func TestNewRendererWithRegistryConfig(t *testing.T) {
+	config := NewConfig()
+	config.SetErrorMode(ErrorModePlaceholder)
+	registry := NewRegistry[string]().
+		Content("a", func(string, FrameInfo) (string, error) { return "ok", nil })
+	renderer, err := NewRendererWithRegistryConfig(config, Exact(Fixed(2), "a"), registry)
+	if err != nil {
+		t.Fatalf("expected complete registry, got %v", err)
+	}
+	if renderer.Config() != config {
+		t.Fatalf("expected renderer to use the provided config")
+	}
+}
@@ -1,9 +1,9 @@
 package keel
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
-	"strconv"
 
 	gloss "github.com/charmbracelet/lipgloss"
 	"github.com/trippwill/keel/core"
@@ -12,98 +12,76 @@ import (
 )
 
 // Render arranges and renders the stored spec at the given size.
+// Frames are painted into a cell buffer (see [Renderer.RenderBuffer]) and the
+// buffer is serialized once, one line per row. Layouts without frames render
+// as an empty string.
 func (r *Renderer[KID]) Render(size Size) (string, error) {
+	return r.RenderContext(context.Background(), size)
+}
+
+// RenderContext is [Renderer.Render] with a context. The context is passed to
+// a [ContentProviderCtx] and to log handlers. Rendering stops before the next
+// frame once ctx is done, returning a [CanceledError].
+func (r *Renderer[KID]) RenderContext(ctx context.Context, size Size) (string, error) {
 	if r == nil {
 		return "", ErrRendererMissing
 	}
 	if r.spec == nil {
 		return "", ErrSpecMissing
 	}
-	layout, err := r.ensureLayout(size)
+	if err := canceled(ctx, ""); err != nil {
+		return "", err
+	}
+	layout, err := r.ensureLayout(ctx, size)
 	if err != nil {
 		return "", convertError(err)
 	}
-	return r.renderLayout(layout)
+	return r.renderLayout(ctx, layout)
 }
 
-func (r *Renderer[KID]) ensureLayout(size Size) (engine.Layout[KID], error) {
-	if r.hasLayout && r.last == size {
-		return r.layout, nil
-	}
-	layout, err := engine.Arrange[KID](r.spec, size, r.config.logger)
-	if err != nil {
-		return engine.Layout[KID]{}, err
+func (r *Renderer[KID]) ensureLayout(ctx context.Context, size Size) (engine.Layout[KID], error) {
+	r.layoutMu.Lock()
+	defer r.layoutMu.Unlock()
+	r.layouts.sync(core.Generation(r.spec))
+	key := layoutKey{size: size}
+	layout, hit := r.layouts.get(key)
+	if !hit {
+		var err error
+		layout, err = engine.ArrangeContext[KID](ctx, r.spec, size, r.config.logger)
+		if err != nil {
+			return engine.Layout[KID]{}, err
+		}
+		r.layouts.put(key, layout, r.config.LayoutCacheSize())
 	}
+	r.logLayoutCache(ctx, key, hit)
 	r.layout = layout
-	r.last = size
 	r.hasLayout = true
 	return layout, nil
 }
 
-func (r *Renderer[KID]) renderLayout(layout engine.Layout[KID]) (string, error) {
-	path := ""
-	logger := rendererLogger(r)
-	if logger != nil {
-		path = "/"
+func (r *Renderer[KID]) renderLayout(ctx context.Context, layout engine.Layout[KID]) (string, error) {
+	buf, painted, frameErr := r.paintLayout(ctx, layout)
+	if buf == nil {
+		return "", frameErr
+	}
+	out := ""
+	if painted {
+		out = bufferString(buf, layout.Height)
 	}
-	out, err := renderLayoutWithPath(layout.Root, r, path)
+	out, err := checkDimensions(ctx, r, out, layout.Root.Rect, layout.Root.Path, nil)
 	if err != nil {
 		return "", convertError(err)
 	}
-	return out, nil
+	return out, frameErr
 }
 
-func renderLayoutWithPath[KID KeelID](node engine.LayoutNode[KID], r *Renderer[KID], path string) (string, error) {
+func renderFrameWithPath[KID KeelID](ctx context.Context, frame core.FrameSpec[KID], r *Renderer[KID], size Size, path string) (string, error) {
 	logger := rendererLogger(r)
-	switch node.Kind {
-	case engine.NodeStack:
-		if len(node.Slots) == 0 {
-			return "", nil
-		}
-		axis := node.Axis
-		if axis != core.AxisHorizontal && axis != core.AxisVertical {
-			err := &core.ConfigError{Reason: core.ErrInvalidAxis}
-			logError(logger, path, "stack.axis", err)
-			return "", err
-		}
-
-		rendered := make([]string, len(node.Slots))
-		for i, slot := range node.Slots {
-			slotPath := path
-			if logger != nil {
-				slotPath = appendPath(path, i)
-			}
-			out, err := renderLayoutWithPath(slot, r, slotPath)
-			if err != nil {
-				logError(logger, path, "stack.render", err)
-				return "", err
-			}
-			rendered[i] = out
-		}
-
-		if axis == core.AxisHorizontal {
-			return gloss.JoinHorizontal(gloss.Top, rendered...), nil
-		}
-		return gloss.JoinVertical(gloss.Left, rendered...), nil
-
-	case engine.NodeFrame:
-		if node.Frame == nil {
-			err := &core.ConfigError{Reason: core.ErrUnknownSpec}
-			logError(logger, path, "dispatch", err)
-			return "", err
-		}
-		size := Size{Width: node.Rect.Width, Height: node.Rect.Height}
-		return renderFrameWithPath(node.Frame, r, size, path)
-	default:
-		err := &core.ConfigError{Reason: core.ErrUnknownSpec}
-		logError(logger, path, "dispatch", err)
+	providedStyle, err := styleForID(ctx, r, frame.ID())
+	if err != nil {
+		logError(ctx, logger, path, "frame.style", err)
 		return "", err
 	}
-}
-
-func renderFrameWithPath[KID KeelID](frame core.FrameSpec[KID], r *Renderer[KID], size Size, path string) (string, error) {
-	logger := rendererLogger(r)
-	providedStyle := styleFor(r, frame)
 
 	// Initialize to default values
 	var (
@@ -134,8 +112,10 @@ func renderFrameWithPath[KID KeelID](frame core.FrameSpec[KID], r *Renderer[KID]
 			Have:   size.Width,
 			Source: sourceFor(frame),
 			Reason: "frame",
+			Path:   path,
+			ID:     frame.ID(),
 		}
-		logError(logger, path, "frame.frame", err)
+		logError(ctx, logger, path, "frame.frame", err)
 		return "", err
 	}
 	if frameHeight > size.Height {
@@ -145,8 +125,10 @@ func renderFrameWithPath[KID KeelID](frame core.FrameSpec[KID], r *Renderer[KID]
 			Have:   size.Height,
 			Source: sourceFor(frame),
 			Reason: "frame",
+			Path:   path,
+			ID:     frame.ID(),
 		}
-		logError(logger, path, "frame.frame", err)
+		logError(ctx, logger, path, "frame.frame", err)
 		return "", err
 	}
 
@@ -161,9 +143,11 @@ func renderFrameWithPath[KID KeelID](frame core.FrameSpec[KID], r *Renderer[KID]
 		FrameWidth:    frameWidth,
 		FrameHeight:   frameHeight,
 		Fit:           frame.Fit(),
+		Focused:       r.focus.IsFocused(frame.ID()),
 	}
 
 	logEvent(
+		ctx,
 		logger,
 		path,
 		logging.EventFrameRender,
@@ -175,11 +159,22 @@ func renderFrameWithPath[KID KeelID](frame core.FrameSpec[KID], r *Renderer[KID]
 		slog.Int("content_width", info.ContentWidth),
 		slog.Int("content_height", info.ContentHeight),
 		slog.String("fit", info.Fit.String()),
+		slog.Bool("focused", info.Focused),
 	)
 
-	content, err := contentFor(r, frame.ID(), info)
+	cacheKey, cached := frameCacheKey(r, frame.ID(), info, providedStyle)
+	if cached {
+		if out, ok := r.cachedOutput(ctx, path, frame.ID(), cacheKey); ok {
+			return out, nil
+		}
+	}
+
+	content, err := contentFor(ctx, r, frame.ID(), info)
 	if err != nil {
-		logError(logger, path, "frame.content", err)
+		if ctx.Err() != nil {
+			err = &CanceledError{Path: path, ID: frame.ID(), Err: err}
+		}
+		logError(ctx, logger, path, "frame.content", err)
 		return "", err
 	}
 
@@ -224,8 +219,10 @@ func renderFrameWithPath[KID KeelID](frame core.FrameSpec[KID], r *Renderer[KID]
 				Have:   size.Width,
 				Source: sourceFor(frame),
 				Reason: "content",
+				Path:   path,
+				ID:     frame.ID(),
 			}
-			logError(logger, path, "frame.content", err)
+			logError(ctx, logger, path, "frame.content", err)
 			return "", err
 		}
 		if contentHeight > availableHeight {
@@ -235,8 +232,10 @@ func renderFrameWithPath[KID KeelID](frame core.FrameSpec[KID], r *Renderer[KID]
 				Have:   size.Height,
 				Source: sourceFor(frame),
 				Reason: "content",
+				Path:   path,
+				ID:     frame.ID(),
 			}
-			logError(logger, path, "frame.content", err)
+			logError(ctx, logger, path, "frame.content", err)
 			return "", err
 		}
 	case core.FitExact:
@@ -248,8 +247,10 @@ func renderFrameWithPath[KID KeelID](frame core.FrameSpec[KID], r *Renderer[KID]
 				Have:   size.Width,
 				Source: sourceFor(frame),
 				Reason: "content",
+				Path:   path,
+				ID:     frame.ID(),
 			}
-			logError(logger, path, "frame.content", err)
+			logError(ctx, logger, path, "frame.content", err)
 			return "", err
 		}
 		if contentHeight > availableHeight {
@@ -259,15 +260,17 @@ func renderFrameWithPath[KID KeelID](frame core.FrameSpec[KID], r *Renderer[KID]
 				Have:   size.Height,
 				Source: sourceFor(frame),
 				Reason: "content",
+				Path:   path,
+				ID:     frame.ID(),
 			}
-			logError(logger, path, "frame.content", err)
+			logError(ctx, logger, path, "frame.content", err)
 			return "", err
 		}
 	case core.FitOverflow:
 		// No fitting or validation; let lipgloss render freely.
 	default:
-		err := &core.ConfigError{}
-		logError(logger, path, "frame.fit", err)
+		err := &core.ConfigError{Path: path, ID: frame.ID()}
+		logError(ctx, logger, path, "frame.fit", err)
 		return "", err
 	}
 
@@ -277,33 +280,60 @@ func renderFrameWithPath[KID KeelID](frame core.FrameSpec[KID], r *Renderer[KID]
 		Width(outerWidth).
 		Height(outerHeight)
 
-	return style.Render(contentToRender), nil
+	out := style.Render(contentToRender)
+	if cached {
+		r.storeOutput(frame.ID(), cacheKey, out)
+	}
+	return out, nil
 }
 
+// styleFor returns the frame style for geometry lookups outside a render
+// pass. A recovered provider panic is logged and treated as no style.
 func styleFor[KID KeelID](r *Renderer[KID], frame core.FrameSpec[KID]) *gloss.Style {
-	if r == nil || r.style == nil {
-		return nil
+	style, _ := styleForID(context.Background(), r, frame.ID())
+	return style
+}
+
+func styleForID[KID KeelID](ctx context.Context, r *Renderer[KID], id KID) (style *gloss.Style, err error) {
+	if r == nil {
+		return nil, nil
+	}
+	if r.config.RecoverPanics() {
+		defer func() {
+			if value := recover(); value != nil {
+				style, err = nil, providerPanic(ctx, rendererLogger(r), id, "style", value)
+			}
+		}()
 	}
-	return r.style(frame.ID())
+	if r.styleChain == nil {
+		return nil, nil
+	}
+	return r.styleChain(ctx, id), nil
 }
 
-func contentFor[KID KeelID](r *Renderer[KID], id KID, info FrameInfo) (string, error) {
-	ecp := effectiveContentProvider(r)
-	if ecp == nil {
+func contentFor[KID KeelID](ctx context.Context, r *Renderer[KID], id KID, info FrameInfo) (content string, err error) {
+	if r == nil {
 		return "", &ContentProviderMissingError{ID: id}
 	}
-
-	return ecp(id, info)
+	if r.config.RecoverPanics() {
+		defer func() {
+			if value := recover(); value != nil {
+				content, err = "", providerPanic(ctx, rendererLogger(r), id, "content", value)
+			}
+		}()
+	}
+	if r.contentChain == nil {
+		return "", &ContentProviderMissingError{ID: id}
+	}
+	return r.contentChain(ctx, id, info)
 }
 
-func effectiveContentProvider[KID KeelID](r *Renderer[KID]) ContentProvider[KID] {
-	if r == nil {
+// canceled returns a [CanceledError] at path when ctx is done.
+func canceled(ctx context.Context, path string) error {
+	if ctx.Err() == nil {
 		return nil
 	}
-	if r.config != nil && r.config.debug {
-		return core.DebugContentProvider[KID]
-	}
-	return r.content
+	return &CanceledError{Path: path, Err: context.Cause(ctx)}
 }
 
 func rendererLogger[KID KeelID](r *Renderer[KID]) *slog.Logger {
@@ -317,17 +347,10 @@ func sourceFor[KID KeelID](frame core.FrameSpec[KID]) string {
 	return fmt.Sprintf("frame %v", frame.ID())
 }
 
-func logEvent(logger *slog.Logger, path string, event logging.Event, attrs ...slog.Attr) {
-	logging.LogEvent(logger, slog.LevelDebug, event, path, attrs...)
+func logEvent(ctx context.Context, logger *slog.Logger, path string, event logging.Event, attrs ...slog.Attr) {
+	logging.LogEventContext(ctx, logger, slog.LevelDebug, event, path, attrs...)
 }
 
-func logError(logger *slog.Logger, path string, stage string, err error) {
-	logging.LogError(logger, path, stage, err)
-}
-
-func appendPath(path string, index int) string {
-	if path == "/" {
-		return "/" + strconv.Itoa(index)
-	}
-	return path + "/" + strconv.Itoa(index)
+func logError(ctx context.Context, logger *slog.Logger, path string, stage string, err error) {
+	logging.LogErrorContext(ctx, logger, path, stage, err)
 }
@@ -1,6 +1,7 @@
 package keel_test
 
 import (
+	"io"
 	"testing"
 
 	"github.com/trippwill/keel"
@@ -23,3 +24,20 @@ func BenchmarkRenderExampleSplit(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkRenderToExampleSplit(b *testing.B) {
+	layout := examples.ExampleSplit()
+	renderer := keel.NewRenderer(
+		layout,
+		examples.ExampleSplitStyleProvider,
+		examples.ExampleSplitContentProvider,
+	)
+	size := keel.Size{Width: 70, Height: 13}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := renderer.RenderTo(io.Discard, size); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
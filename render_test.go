@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"strings"
+	"sync"
 	"testing"
 
 	gloss "github.com/charmbracelet/lipgloss"
@@ -61,6 +62,7 @@ type countingStack struct {
 }
 
 type captureHandler struct {
+	mu      *sync.Mutex
 	entries *[]logEntry
 	attrs   []slog.Attr
 	groups  []string
@@ -68,7 +70,7 @@ type captureHandler struct {
 
 func newCaptureHandler() (*captureHandler, *[]logEntry) {
 	entries := []logEntry{}
-	return &captureHandler{entries: &entries}, &entries
+	return &captureHandler{mu: &sync.Mutex{}, entries: &entries}, &entries
 }
 
 func (h *captureHandler) Enabled(context.Context, slog.Level) bool {
@@ -94,6 +96,8 @@ func (h *captureHandler) Handle(_ context.Context, record slog.Record) error {
 		return true
 	})
 
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	*h.entries = append(*h.entries, logEntry{
 		level: record.Level,
 		msg:   record.Message,
@@ -106,6 +110,7 @@ func (h *captureHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	nextAttrs := append([]slog.Attr{}, h.attrs...)
 	nextAttrs = append(nextAttrs, attrs...)
 	return &captureHandler{
+		mu:      h.mu,
 		entries: h.entries,
 		attrs:   nextAttrs,
 		groups:  append([]string{}, h.groups...),
@@ -116,6 +121,7 @@ func (h *captureHandler) WithGroup(name string) slog.Handler {
 	nextGroups := append([]string{}, h.groups...)
 	nextGroups = append(nextGroups, name)
 	return &captureHandler{
+		mu:      h.mu,
 		entries: h.entries,
 		attrs:   append([]slog.Attr{}, h.attrs...),
 		groups:  nextGroups,
@@ -191,7 +197,7 @@ func TestRenderMatchesLayoutRender(t *testing.T) {
 		t.Fatalf("unexpected arrange error: %v", err)
 	}
 
-	got, err := renderer.renderLayout(arranged)
+	got, err := renderer.renderLayout(context.Background(), arranged)
 	if err != nil {
 		t.Fatalf("unexpected layout render error: %v", err)
 	}
@@ -364,6 +370,34 @@ func TestRender_LoggerEvents(t *testing.T) {
 	}
 }
 
+func TestRender_LoggerTraceExplain(t *testing.T) {
+	handler, entries := newCaptureHandler()
+	logger := slog.New(handler)
+
+	layout := Row(FlexUnit(),
+		Exact(Fixed(1), "a"),
+		Exact(FlexUnit(), "b"),
+	)
+	renderer := NewRenderer(layout, nil, makeContentProvider(""))
+	renderer.Config().SetLogger(logger)
+
+	if _, err := renderer.Render(Size{Width: 3, Height: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := (*entries)[0]
+	if first.attrs["event"] != string(logging.EventStackAlloc) {
+		t.Fatalf("expected stack.alloc first, got %q", first.attrs["event"])
+	}
+	if first.level != logging.LevelTrace {
+		t.Fatalf("expected trace level, got %v", first.level)
+	}
+	explain, ok := first.attrs["explain"].(string)
+	if !ok || !strings.Contains(explain, "remainder") {
+		t.Fatalf("expected explain table, got %v", first.attrs["explain"])
+	}
+}
+
 func TestRender_LoggerError(t *testing.T) {
 	handler, entries := newCaptureHandler()
 	logger := slog.New(handler)
@@ -613,6 +647,40 @@ func TestRenderPanel_ContentTooWide(t *testing.T) {
 	}
 }
 
+func TestRenderPanel_NestedErrorLocation(t *testing.T) {
+	layout := Row(FlexUnit(),
+		Exact(Fixed(2), "a"),
+		Col(FlexUnit(),
+			Exact(FlexUnit(), "b"),
+			Row(FlexUnit(), nil),
+		),
+	)
+	renderer := NewRenderer(layout, nil, makeContentProvider("abcd"))
+	size := Size{Width: 6, Height: 2}
+
+	_, err := renderer.Render(size)
+	var specErr *SpecError
+	if !errors.As(err, &specErr) {
+		t.Fatalf("expected SpecError, got %v", err)
+	}
+	if specErr.Path != "/1/1" || specErr.Index != 0 {
+		t.Fatalf("expected slot 0 at /1/1, got %+v", specErr)
+	}
+
+	renderer = NewRenderer(Row(FlexUnit(),
+		Exact(Fixed(4), "a"),
+		Col(FlexUnit(), Exact(FlexUnit(), "b")),
+	), nil, makeContentProvider("abcd"))
+	_, err = renderer.Render(size)
+	var tooSmall *ExtentTooSmallError
+	if !errors.As(err, &tooSmall) {
+		t.Fatalf("expected ExtentTooSmallError, got %v", err)
+	}
+	if tooSmall.Path != "/1/0" || tooSmall.ID != "b" {
+		t.Fatalf("expected frame b at /1/0, got %+v", tooSmall)
+	}
+}
+
 func TestRenderPanel_FitClipTruncatesContent(t *testing.T) {
 	panel := Clip(FlexUnit(), "a")
 	renderer := NewRenderer(panel, nil, makeContentProvider("abcd"))
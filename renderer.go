@@ -1,7 +1,13 @@
 package keel
 
 import (
+	"bytes"
+	"context"
+	"sync"
+
 	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/trippwill/keel/core"
 	"github.com/trippwill/keel/engine"
 )
 
@@ -15,25 +21,53 @@ type StyleProvider[KID KeelID] func(id KID) *gloss.Style
 // FitMode will be applied after content is retrieved.
 type ContentProvider[KID KeelID] func(id KID, info FrameInfo) (string, error)
 
+// ContentProviderCtx is a [ContentProvider] that receives the render context,
+// so slow providers can stop when a render is canceled or its deadline passes.
+type ContentProviderCtx[KID KeelID] func(ctx context.Context, id KID, info FrameInfo) (string, error)
+
+// StyleProviderCtx is a [StyleProvider] that receives the render context. It
+// is the form of provider that [StyleMiddleware] wraps.
+type StyleProviderCtx[KID KeelID] func(ctx context.Context, id KID) *gloss.Style
+
 // Renderer owns render providers and uses a shared config for logging/debugging.
+//
+// Render, RenderContext, RenderBuffer and Layout are safe for concurrent use,
+// so one renderer can serve several sessions at different sizes. RenderTo,
+// RenderDiff and the methods that change providers, config or state are not.
+// Methods that read the arranged layout, such as RenderFrame, ContentAt,
+// [Router.Dispatch] and [Focus.Move], use the layout of the most recent
+// render whatever its size, and act as if nothing had been arranged after an
+// edit to a versioned spec until the next render.
 type Renderer[KID KeelID] struct {
-	config    *Config
-	spec      Spec
-	style     StyleProvider[KID]
-	content   ContentProvider[KID]
+	config   *Config
+	spec     Spec
+	style    StyleProvider[KID]
+	content  ContentProvider[KID]
+	previous *cellbuf.Buffer
+	focus    *Focus[KID]
+
+	layoutMu  sync.Mutex
 	layout    engine.Layout[KID]
-	last      Size
 	hasLayout bool
+	layouts   layoutCache[KID]
+
+	contentCtx      ContentProviderCtx[KID]
+	middleware      []ContentMiddleware[KID]
+	styleMiddleware []StyleMiddleware[KID]
+	contentChain    ContentProviderCtx[KID]
+	styleChain      StyleProviderCtx[KID]
+	version         VersionProvider[KID]
+	cacheMu         sync.Mutex
+	cache           map[KID]cachedFrame
+	line            bytes.Buffer
+	stateStyle      StatefulStyleProvider[KID]
+	states          map[KID]FrameState
+	revision        uint64
 }
 
 // NewRenderer returns a renderer for the given spec with a fresh config.
 func NewRenderer[KID KeelID](spec Spec, styleProvider StyleProvider[KID], contentProvider ContentProvider[KID]) *Renderer[KID] {
-	return &Renderer[KID]{
-		config:  NewConfig(),
-		spec:    spec,
-		style:   styleProvider,
-		content: contentProvider,
-	}
+	return newRenderer(NewConfig(), spec, styleProvider, contentProvider)
 }
 
 // NewRendererWithConfig returns a renderer for the given spec using the provided config.
@@ -41,12 +75,19 @@ func NewRendererWithConfig[KID KeelID](config *Config, spec Spec, styleProvider
 	if config == nil {
 		config = NewConfig()
 	}
-	return &Renderer[KID]{
+	return newRenderer(config, spec, styleProvider, contentProvider)
+}
+
+func newRenderer[KID KeelID](config *Config, spec Spec, styleProvider StyleProvider[KID], contentProvider ContentProvider[KID]) *Renderer[KID] {
+	r := &Renderer[KID]{
 		config:  config,
 		spec:    spec,
 		style:   styleProvider,
 		content: contentProvider,
 	}
+	r.buildContentChain()
+	r.buildStyleChain()
+	return r
 }
 
 // Config returns the renderer's config, allocating one if needed.
@@ -73,12 +114,25 @@ func (r *Renderer[KID]) SetConfig(config *Config) {
 	r.Invalidate()
 }
 
+// SetSpec replaces the renderer spec, for example with a tree returned by
+// [InsertSlot] or [RemoveFrame].
+// Invalidates cached layout state.
+func (r *Renderer[KID]) SetSpec(spec Spec) {
+	if r == nil {
+		return
+	}
+	r.spec = spec
+	r.Invalidate()
+}
+
 // SetStyleProvider replaces the renderer style provider.
 func (r *Renderer[KID]) SetStyleProvider(p StyleProvider[KID]) {
 	if r == nil {
 		return
 	}
 	r.style = p
+	r.buildStyleChain()
+	r.revision++
 }
 
 // SetContentProvider replaces the renderer content provider.
@@ -87,12 +141,76 @@ func (r *Renderer[KID]) SetContentProvider(p ContentProvider[KID]) {
 		return
 	}
 	r.content = p
+	r.buildContentChain()
+	r.clearFrameCache()
 }
 
-// Invalidate clears cached layout state.
+// SetContentProviderCtx replaces the renderer content provider with one that
+// receives the render context. It takes precedence over a [ContentProvider];
+// pass nil to fall back to it.
+func (r *Renderer[KID]) SetContentProviderCtx(p ContentProviderCtx[KID]) {
+	if r == nil {
+		return
+	}
+	r.contentCtx = p
+	r.buildContentChain()
+	r.clearFrameCache()
+}
+
+// Invalidate clears cached layout state, including every layout in the
+// layout cache.
 func (r *Renderer[KID]) Invalidate() {
 	if r == nil {
 		return
 	}
+	r.layoutMu.Lock()
+	defer r.layoutMu.Unlock()
 	r.hasLayout = false
+	r.layouts.reset()
+}
+
+// lastLayout returns the layout arranged by the most recent render or
+// [Renderer.Layout] call, whatever its size. It returns false when the spec
+// generation has changed since, so readers never act on a stale tree.
+func (r *Renderer[KID]) lastLayout() (engine.Layout[KID], bool) {
+	r.layoutMu.Lock()
+	defer r.layoutMu.Unlock()
+	if !r.hasLayout || core.Generation(r.spec) != r.layouts.generation {
+		return engine.Layout[KID]{}, false
+	}
+	return r.layout, true
+}
+
+// Layout returns the arranged layout for the given size, re-arranging only
+// when the size differs from the cached layout.
+func (r *Renderer[KID]) Layout(size Size) (engine.Layout[KID], error) {
+	if r == nil {
+		return engine.Layout[KID]{}, ErrRendererMissing
+	}
+	if r.spec == nil {
+		return engine.Layout[KID]{}, ErrSpecMissing
+	}
+	layout, err := r.ensureLayout(context.Background(), size)
+	if err != nil {
+		return engine.Layout[KID]{}, convertError(err)
+	}
+	return layout, nil
+}
+
+// FrameInfo returns the [FrameInfo] the content provider receives for an
+// arranged frame node. Content sizes are clamped at zero when the style frame
+// does not fit the allocation.
+func (r *Renderer[KID]) FrameInfo(node engine.LayoutNode[KID]) FrameInfo {
+	info := FrameInfo{Width: node.Rect.Width, Height: node.Rect.Height}
+	if node.Frame == nil {
+		return info
+	}
+	info.Fit = node.Frame.Fit()
+	info.Focused = r.focus.IsFocused(node.Frame.ID())
+	if style := styleFor(r, node.Frame); style != nil {
+		info.FrameWidth, info.FrameHeight = style.GetFrameSize()
+	}
+	info.ContentWidth = max(info.Width-info.FrameWidth, 0)
+	info.ContentHeight = max(info.Height-info.FrameHeight, 0)
+	return info
 }
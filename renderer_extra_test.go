@@ -1,9 +1,11 @@
 package keel
 
 import (
+	"context"
 	"testing"
 
 	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/trippwill/keel/core"
 )
 
 func TestRendererSetters(t *testing.T) {
@@ -15,7 +17,7 @@ func TestRendererSetters(t *testing.T) {
 		t.Fatalf("expected style")
 	}
 	renderer.SetContentProvider(func(id string, info FrameInfo) (string, error) { return "ok", nil })
-	out, err := contentFor(renderer, "a", FrameInfo{})
+	out, err := contentFor(context.Background(), renderer, "a", FrameInfo{})
 	if err != nil || out != "ok" {
 		t.Fatalf("expected content provider to run")
 	}
@@ -49,3 +51,45 @@ func TestRendererDebugUsesDebugProvider(t *testing.T) {
 		t.Fatalf("expected debug output")
 	}
 }
+
+func TestRendererLayoutAndFrameInfo(t *testing.T) {
+	layout := Row(FlexUnit(),
+		Clip(Fixed(2), "a"),
+		Wrap(FlexUnit(), "b"),
+	)
+	style := gloss.NewStyle().Padding(0, 1)
+	renderer := NewRenderer(layout, func(id string) *gloss.Style {
+		if id == "b" {
+			return &style
+		}
+		return nil
+	}, nil)
+
+	arranged, err := renderer.Layout(Size{Width: 6, Height: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !renderer.hasLayout {
+		t.Fatalf("expected cached layout")
+	}
+	node, ok := arranged.Frame("b")
+	if !ok {
+		t.Fatalf("expected frame b")
+	}
+	want := FrameInfo{
+		Width:         4,
+		Height:        2,
+		ContentWidth:  2,
+		ContentHeight: 2,
+		FrameWidth:    2,
+		Fit:           core.FitWrapClip,
+	}
+	if got := renderer.FrameInfo(node); got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	var missing *Renderer[string]
+	if _, err := missing.Layout(Size{Width: 1, Height: 1}); err == nil {
+		t.Fatalf("expected error for nil renderer")
+	}
+}
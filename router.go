@@ -0,0 +1,202 @@
+package keel
+
+import (
+	"github.com/trippwill/keel/engine"
+)
+
+// MouseButton identifies the button of a [MouseEvent].
+type MouseButton uint8
+
+const (
+	// MouseButtonNone is used for motion events without a pressed button.
+	MouseButtonNone MouseButton = iota
+	// MouseButtonLeft is the primary button.
+	MouseButtonLeft
+	// MouseButtonMiddle is the middle button, usually the wheel click.
+	MouseButtonMiddle
+	// MouseButtonRight is the secondary button.
+	MouseButtonRight
+	// MouseWheelUp scrolls up.
+	MouseWheelUp
+	// MouseWheelDown scrolls down.
+	MouseWheelDown
+	// MouseWheelLeft scrolls left.
+	MouseWheelLeft
+	// MouseWheelRight scrolls right.
+	MouseWheelRight
+)
+
+// IsWheel reports whether the button is a scroll wheel direction.
+func (b MouseButton) IsWheel() bool {
+	return b >= MouseWheelUp && b <= MouseWheelRight
+}
+
+// MouseAction identifies the action of a [MouseEvent].
+type MouseAction uint8
+
+const (
+	// MousePress is a button press or a wheel step.
+	MousePress MouseAction = iota
+	// MouseRelease is a button release.
+	MouseRelease
+	// MouseMotion is pointer movement, with or without a pressed button.
+	MouseMotion
+)
+
+// MouseEvent is a terminal mouse event. X and Y are cell coordinates; the
+// [Router] translates them before calling handlers.
+type MouseEvent struct {
+	X, Y   int
+	Button MouseButton
+	Action MouseAction
+}
+
+// MouseHandler handles a routed [MouseEvent].
+type MouseHandler func(ev MouseEvent)
+
+// ContentHit describes the frame under a point. X and Y are relative to the
+// frame's content box, so they are negative or past the content size when the
+// point is over padding, border or margin; Inside reports whether the point
+// is within the content box.
+type ContentHit[KID KeelID] struct {
+	ID     KID
+	Path   string
+	Info   FrameInfo
+	X, Y   int
+	Inside bool
+}
+
+// ContentAt locates the frame under the point (x, y) in the cached layout from
+// the last render, whatever its size. It returns false when nothing has been
+// arranged since the spec was created or edited, or no frame contains the
+// point.
+func (r *Renderer[KID]) ContentAt(x, y int) (ContentHit[KID], bool) {
+	if r == nil {
+		return ContentHit[KID]{}, false
+	}
+	layout, ok := r.lastLayout()
+	if !ok {
+		return ContentHit[KID]{}, false
+	}
+	node, ok := layout.FrameAt(x, y)
+	if !ok {
+		return ContentHit[KID]{}, false
+	}
+	return r.contentHit(node, x, y), true
+}
+
+func (r *Renderer[KID]) contentHit(node engine.LayoutNode[KID], x, y int) ContentHit[KID] {
+	hit := ContentHit[KID]{
+		ID:   node.Frame.ID(),
+		Path: node.Path,
+		Info: r.FrameInfo(node),
+		X:    x - node.Rect.X,
+		Y:    y - node.Rect.Y,
+	}
+	if style := styleFor(r, node.Frame); style != nil {
+		hit.X -= style.GetMarginLeft() + style.GetBorderLeftSize() + style.GetPaddingLeft()
+		hit.Y -= style.GetMarginTop() + style.GetBorderTopSize() + style.GetPaddingTop()
+	}
+	hit.Inside = hit.X >= 0 && hit.X < hit.Info.ContentWidth &&
+		hit.Y >= 0 && hit.Y < hit.Info.ContentHeight
+	return hit
+}
+
+// Router dispatches mouse events to handlers registered per frame, using the
+// layout cached by the renderer's last render. When the renderer serves
+// several sizes, dispatch right after rendering the size the event belongs to.
+//
+// Button and motion events go to the handler of the frame under the pointer,
+// in content-box coordinates (see [ContentHit]). Wheel events go to the scroll
+// handler of that frame, or bubble to the nearest enclosing stack with a
+// scroll handler, in coordinates relative to the stack's rect.
+type Router[KID KeelID] struct {
+	renderer *Renderer[KID]
+	handlers map[KID]MouseHandler
+	scroll   map[KID]MouseHandler
+	stacks   map[string]MouseHandler
+}
+
+// NewRouter returns a router over the renderer's cached layout.
+func NewRouter[KID KeelID](renderer *Renderer[KID]) *Router[KID] {
+	return &Router[KID]{
+		renderer: renderer,
+		handlers: map[KID]MouseHandler{},
+		scroll:   map[KID]MouseHandler{},
+		stacks:   map[string]MouseHandler{},
+	}
+}
+
+// Handle registers the button and motion handler for frame id.
+// A nil handler removes the registration.
+func (r *Router[KID]) Handle(id KID, h MouseHandler) {
+	setHandler(r.handlers, id, h)
+}
+
+// HandleScroll registers the wheel handler for frame id.
+// A nil handler removes the registration.
+func (r *Router[KID]) HandleScroll(id KID, h MouseHandler) {
+	setHandler(r.scroll, id, h)
+}
+
+// HandleStackScroll registers a wheel handler for the stack at the given
+// layout path (e.g. "/1"). Stacks have no ID, so the path is the only way to
+// address them. A nil handler removes the registration.
+func (r *Router[KID]) HandleStackScroll(path string, h MouseHandler) {
+	setHandler(r.stacks, path, h)
+}
+
+// Dispatch routes ev and reports whether a handler was called. After an edit
+// bumps the generation of a versioned spec, nothing is dispatched until the
+// next render.
+func (r *Router[KID]) Dispatch(ev MouseEvent) bool {
+	if r == nil || r.renderer == nil {
+		return false
+	}
+	layout, ok := r.renderer.lastLayout()
+	if !ok {
+		return false
+	}
+	nodes := layout.NodesAt(ev.X, ev.Y)
+	if len(nodes) == 0 {
+		return false
+	}
+
+	leaf := nodes[len(nodes)-1]
+	if leaf.Kind == engine.NodeFrame && leaf.Frame != nil {
+		handlers := r.handlers
+		if ev.Button.IsWheel() {
+			handlers = r.scroll
+		}
+		if h, ok := handlers[leaf.Frame.ID()]; ok {
+			hit := r.renderer.contentHit(leaf, ev.X, ev.Y)
+			local := ev
+			local.X, local.Y = hit.X, hit.Y
+			h(local)
+			return true
+		}
+		nodes = nodes[:len(nodes)-1]
+	}
+	if !ev.Button.IsWheel() {
+		return false
+	}
+
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if h, ok := r.stacks[nodes[i].Path]; ok {
+			local := ev
+			local.X -= nodes[i].Rect.X
+			local.Y -= nodes[i].Rect.Y
+			h(local)
+			return true
+		}
+	}
+	return false
+}
+
+func setHandler[K comparable](handlers map[K]MouseHandler, key K, h MouseHandler) {
+	if h == nil {
+		delete(handlers, key)
+		return
+	}
+	handlers[key] = h
+}
@@ -0,0 +1,129 @@
+package keel
+
+import (
+	"testing"
+
+	gloss "github.com/charmbracelet/lipgloss"
+)
+
+func routerRenderer(t *testing.T) *Renderer[string] {
+	t.Helper()
+	layout := Row(FlexUnit(),
+		Clip(Fixed(4), "nav"),
+		Col(FlexUnit(),
+			Clip(Fixed(1), "title"),
+			Clip(FlexUnit(), "body"),
+		),
+	)
+	style := gloss.NewStyle().Border(gloss.NormalBorder()).Padding(0, 1)
+	renderer := NewRenderer(layout, func(id string) *gloss.Style {
+		if id == "body" {
+			return &style
+		}
+		return nil
+	}, makeContentProvider(""))
+	if _, err := renderer.Render(Size{Width: 12, Height: 6}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return renderer
+}
+
+func TestContentAt(t *testing.T) {
+	var empty *Renderer[string]
+	if _, ok := empty.ContentAt(0, 0); ok {
+		t.Fatalf("expected no hit for nil renderer")
+	}
+	if _, ok := NewRenderer[string](Clip(FlexUnit(), "a"), nil, nil).ContentAt(0, 0); ok {
+		t.Fatalf("expected no hit before render")
+	}
+
+	renderer := routerRenderer(t)
+	hit, ok := renderer.ContentAt(6, 2)
+	if !ok {
+		t.Fatalf("expected hit")
+	}
+	if hit.ID != "body" || hit.Path != "/1/1" || hit.X != 0 || hit.Y != 0 || !hit.Inside {
+		t.Fatalf("unexpected hit %+v", hit)
+	}
+	if hit.Info.ContentWidth != 4 || hit.Info.ContentHeight != 3 {
+		t.Fatalf("unexpected content box %+v", hit.Info)
+	}
+
+	hit, ok = renderer.ContentAt(4, 1)
+	if !ok || hit.ID != "body" || hit.X != -2 || hit.Y != -1 || hit.Inside {
+		t.Fatalf("expected hit on border outside the content box, got %+v", hit)
+	}
+	if _, ok := renderer.ContentAt(12, 0); ok {
+		t.Fatalf("expected no hit outside the layout")
+	}
+}
+
+func TestRouterDispatch(t *testing.T) {
+	renderer := routerRenderer(t)
+	router := NewRouter(renderer)
+
+	var got []MouseEvent
+	var target []string
+	record := func(name string) MouseHandler {
+		return func(ev MouseEvent) {
+			target = append(target, name)
+			got = append(got, ev)
+		}
+	}
+	router.Handle("body", record("body"))
+	router.Handle("nav", record("nav"))
+
+	if !router.Dispatch(MouseEvent{X: 7, Y: 3, Button: MouseButtonLeft, Action: MousePress}) {
+		t.Fatalf("expected dispatch to body")
+	}
+	want := MouseEvent{X: 1, Y: 1, Button: MouseButtonLeft, Action: MousePress}
+	if target[0] != "body" || got[0] != want {
+		t.Fatalf("expected %+v on body, got %v %+v", want, target, got)
+	}
+
+	if router.Dispatch(MouseEvent{X: 5, Y: 0, Button: MouseButtonLeft}) {
+		t.Fatalf("expected no handler for title")
+	}
+
+	router.Handle("nav", nil)
+	if router.Dispatch(MouseEvent{X: 0, Y: 0, Button: MouseButtonLeft}) {
+		t.Fatalf("expected removed handler not to run")
+	}
+}
+
+func TestRouterWheelBubbles(t *testing.T) {
+	renderer := routerRenderer(t)
+	router := NewRouter(renderer)
+
+	var target string
+	var got MouseEvent
+	record := func(name string) MouseHandler {
+		return func(ev MouseEvent) {
+			target, got = name, ev
+		}
+	}
+	router.Handle("title", record("title-click"))
+	router.HandleScroll("body", record("body"))
+	router.HandleStackScroll("/1", record("/1"))
+	router.HandleStackScroll("/", record("/"))
+
+	router.Dispatch(MouseEvent{X: 6, Y: 3, Button: MouseWheelDown})
+	if target != "body" || got.X != 0 || got.Y != 1 {
+		t.Fatalf("expected body scroll handler, got %s %+v", target, got)
+	}
+
+	router.Dispatch(MouseEvent{X: 6, Y: 0, Button: MouseWheelUp})
+	if target != "/1" || got.X != 2 || got.Y != 0 || got.Button != MouseWheelUp {
+		t.Fatalf("expected wheel to bubble to /1, got %s %+v", target, got)
+	}
+
+	router.Dispatch(MouseEvent{X: 1, Y: 2, Button: MouseWheelDown})
+	if target != "/" || got.X != 1 || got.Y != 2 {
+		t.Fatalf("expected wheel to bubble to /, got %s %+v", target, got)
+	}
+
+	router.HandleStackScroll("/", nil)
+	if router.Dispatch(MouseEvent{X: 1, Y: 2, Button: MouseWheelDown}) {
+		t.Fatalf("expected no scroll handler for nav")
+	}
+}
@@ -16,3 +16,15 @@ func Row(size ExtentConstraint, slots ...Spec) StackSpec {
 func Col(size ExtentConstraint, slots ...Spec) StackSpec {
 	return engine.NewSplitSpec(core.AxisVertical, size, slots...)
 }
+
+// MutableRow creates a horizontal split that can be edited in place.
+// Renderers re-arrange after each edit without [Renderer.Invalidate].
+func MutableRow(size ExtentConstraint, slots ...Spec) *engine.SplitBuilder {
+	return engine.NewSplitBuilder(core.AxisHorizontal, size, slots...)
+}
+
+// MutableCol creates a vertical split that can be edited in place.
+// Renderers re-arrange after each edit without [Renderer.Invalidate].
+func MutableCol(size ExtentConstraint, slots ...Spec) *engine.SplitBuilder {
+	return engine.NewSplitBuilder(core.AxisVertical, size, slots...)
+}
@@ -0,0 +1,151 @@
+package keel
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/trippwill/keel/logging"
+)
+
+// FrameState is a set of interaction states passed to a
+// [StatefulStyleProvider].
+type FrameState uint8
+
+const (
+	// StateFocused is set by the [Focus] manager for the focused frame.
+	StateFocused FrameState = 1 << iota
+	// StateHovered marks a frame under the pointer.
+	StateHovered
+	// StateActive marks a frame being pressed or otherwise activated.
+	StateActive
+	// StateDisabled marks a frame that does not accept input.
+	StateDisabled
+	// StateError marks a frame showing an error.
+	StateError
+)
+
+// Has reports whether every state in other is set.
+func (s FrameState) Has(other FrameState) bool {
+	return s&other == other
+}
+
+func (s FrameState) String() string {
+	if s == 0 {
+		return "none"
+	}
+	names := []string{"focused", "hovered", "active", "disabled", "error"}
+	var parts []string
+	for i, name := range names {
+		if s&(1<<i) != 0 {
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+// StatefulStyleProvider returns a style for the given frame ID and state.
+// Nil means "no style". The same caching rules as [StyleProvider] apply.
+type StatefulStyleProvider[KID KeelID] func(id KID, state FrameState) *gloss.Style
+
+// SetStatefulStyleProvider replaces the renderer style provider with one that
+// receives frame state. It takes precedence over a [StyleProvider]; pass nil
+// to fall back to it.
+func (r *Renderer[KID]) SetStatefulStyleProvider(p StatefulStyleProvider[KID]) {
+	if r == nil {
+		return
+	}
+	r.stateStyle = p
+	r.buildStyleChain()
+	r.revision++
+}
+
+// State returns the state of frame id: the state set with
+// [Renderer.SetState], plus [StateFocused] when the frame has focus.
+func (r *Renderer[KID]) State(id KID) FrameState {
+	if r == nil {
+		return 0
+	}
+	state := r.states[id]
+	if r.focus.IsFocused(id) {
+		state |= StateFocused
+	}
+	return state
+}
+
+// SetState replaces the state of frame id. [StateFocused] is managed by
+// [Renderer.Focus] and ignored here.
+//
+// State never affects allocation, so the cached layout is kept. SetState
+// reports whether the frame's style changed its frame size (for example a
+// border toggled on focus); in that case the content box changed and
+// [Renderer.FrameRevision] is incremented so callers that cache content
+// sizes can re-validate them. Color-only changes report false.
+func (r *Renderer[KID]) SetState(id KID, state FrameState) bool {
+	if r == nil {
+		return false
+	}
+	return r.restyle(func() {
+		state &^= StateFocused
+		if state == 0 {
+			delete(r.states, id)
+			return
+		}
+		if r.states == nil {
+			r.states = map[KID]FrameState{}
+		}
+		r.states[id] = state
+	}, id)
+}
+
+// FrameRevision increments whenever a state or style provider change may
+// have altered a frame size, and therefore a content box.
+func (r *Renderer[KID]) FrameRevision() uint64 {
+	if r == nil {
+		return 0
+	}
+	return r.revision
+}
+
+// restyle applies change and compares the frame sizes of ids before and
+// after, bumping the revision when any differ.
+func (r *Renderer[KID]) restyle(change func(), ids ...KID) bool {
+	before := make([][2]int, len(ids))
+	for i, id := range ids {
+		before[i] = frameSizeFor(r, id)
+	}
+	change()
+	ctx := context.Background()
+	changed := false
+	for i, id := range ids {
+		after := frameSizeFor(r, id)
+		if after == before[i] {
+			continue
+		}
+		changed = true
+		logEvent(
+			ctx,
+			rendererLogger(r),
+			"",
+			logging.EventFrameRestyle,
+			slog.Any("id", id),
+			slog.String("state", r.State(id).String()),
+			slog.Int("frame_width", after[0]),
+			slog.Int("frame_height", after[1]),
+		)
+	}
+	if changed {
+		r.revision++
+	}
+	return changed
+}
+
+func frameSizeFor[KID KeelID](r *Renderer[KID], id KID) [2]int {
+	style, _ := styleForID(context.Background(), r, id)
+	if style == nil {
+		return [2]int{}
+	}
+	width, height := style.GetFrameSize()
+	return [2]int{width, height}
+}
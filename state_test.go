@@ -0,0 +1,118 @@
+package keel
+
+import (
+	"log/slog"
+	"testing"
+
+	gloss "github.com/charmbracelet/lipgloss"
+	"github.com/trippwill/keel/logging"
+)
+
+func stateRenderer() (*Renderer[string], *[]logEntry) {
+	layout := Row(FlexUnit(),
+		Clip(FlexUnit(), "a"),
+		Clip(FlexUnit(), "b"),
+	)
+	renderer := NewRenderer(layout, nil, makeContentProvider("x"))
+	renderer.SetStatefulStyleProvider(func(id string, state FrameState) *gloss.Style {
+		style := gloss.NewStyle()
+		if state.Has(StateFocused) {
+			style = style.Border(gloss.NormalBorder())
+		}
+		if state.Has(StateError) {
+			style = style.Foreground(gloss.Color("1"))
+		}
+		return &style
+	})
+	handler, entries := newCaptureHandler()
+	renderer.Config().SetLogger(slog.New(handler))
+	return renderer, entries
+}
+
+func TestFrameStateString(t *testing.T) {
+	if got := FrameState(0).String(); got != "none" {
+		t.Fatalf("expected none, got %q", got)
+	}
+	if got := (StateFocused | StateError).String(); got != "focused|error" {
+		t.Fatalf("expected focused|error, got %q", got)
+	}
+	if !(StateHovered | StateActive).Has(StateActive) || StateHovered.Has(StateHovered|StateActive) {
+		t.Fatalf("unexpected Has result")
+	}
+}
+
+func TestSetStateColorDoesNotRearrange(t *testing.T) {
+	renderer, entries := stateRenderer()
+	size := Size{Width: 6, Height: 3}
+	if _, err := renderer.Render(size); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	revision := renderer.FrameRevision()
+
+	if renderer.SetState("a", StateError|StateFocused) {
+		t.Fatalf("expected color-only change to keep the frame size")
+	}
+	if renderer.State("a") != StateError {
+		t.Fatalf("expected focused to be ignored, got %v", renderer.State("a"))
+	}
+	if !renderer.hasLayout || renderer.FrameRevision() != revision {
+		t.Fatalf("expected cached layout and revision to be kept")
+	}
+	for _, entry := range *entries {
+		if entry.attrs["event"] == string(logging.EventFrameRestyle) {
+			t.Fatalf("unexpected restyle event: %+v", entry)
+		}
+	}
+
+	renderer.SetState("a", 0)
+	if renderer.State("a") != 0 {
+		t.Fatalf("expected state cleared")
+	}
+}
+
+func TestFocusBorderRevalidates(t *testing.T) {
+	renderer, entries := stateRenderer()
+	size := Size{Width: 6, Height: 3}
+	revision := renderer.FrameRevision()
+
+	renderer.Focus().Set("a")
+	if renderer.State("a") != StateFocused {
+		t.Fatalf("expected focused state")
+	}
+	if renderer.FrameRevision() != revision+1 {
+		t.Fatalf("expected revision bump for border change")
+	}
+	out, err := renderer.Render(size)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "┌─┐x  \n│x│   \n└─┘   "
+	if out != want {
+		t.Fatalf("expected %q, got %q", want, out)
+	}
+
+	arranged, _ := renderer.Layout(size)
+	node, _ := arranged.Frame("a")
+	if info := renderer.FrameInfo(node); info.ContentWidth != 1 || info.ContentHeight != 1 || !info.Focused {
+		t.Fatalf("expected bordered content box, got %+v", info)
+	}
+
+	renderer.Focus().Next()
+	if renderer.FrameRevision() != revision+2 {
+		t.Fatalf("expected one revision bump for moving focus")
+	}
+	restyled := 0
+	for _, entry := range *entries {
+		if entry.attrs["event"] == string(logging.EventFrameRestyle) {
+			restyled++
+		}
+	}
+	if restyled != 3 {
+		t.Fatalf("expected 3 restyle events, got %d", restyled)
+	}
+
+	renderer.Focus().Set("b")
+	if renderer.FrameRevision() != revision+2 {
+		t.Fatalf("expected no revision bump when focus does not change")
+	}
+}
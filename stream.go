@@ -0,0 +1,68 @@
+package keel
+
+import (
+	"context"
+	"io"
+
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+// RenderTo renders the stored spec at the given size like [Renderer.Render]
+// and writes the output to w one line at a time, without building the whole
+// string. The line buffer is reused between calls, so a renderer must not
+// stream to several writers concurrently. The bytes written are identical to
+// the string Render returns.
+//
+// With a [DimensionCheck] enabled the whole output must be checked before it
+// is written, so RenderTo falls back to writing the result of Render.
+// Partial output from [ErrorModePlaceholder] is written before the frame
+// errors are returned.
+func (r *Renderer[KID]) RenderTo(w io.Writer, size Size) error {
+	if r == nil {
+		return ErrRendererMissing
+	}
+	if r.spec == nil {
+		return ErrSpecMissing
+	}
+	if r.config.DimensionCheck() != DimensionCheckOff {
+		out, err := r.Render(size)
+		if _, werr := io.WriteString(w, out); werr != nil {
+			return werr
+		}
+		return err
+	}
+
+	ctx := context.Background()
+	layout, err := r.ensureLayout(ctx, size)
+	if err != nil {
+		return convertError(err)
+	}
+	buf, painted, frameErr := r.paintLayout(ctx, layout)
+	if buf == nil || !painted {
+		return frameErr
+	}
+	if err := r.writeBuffer(w, buf, layout.Height); err != nil {
+		return err
+	}
+	return frameErr
+}
+
+// writeBuffer writes the serialized buffer to w one line at a time, using the
+// renderer's reusable line buffer. Like [bufferString], it writes at least
+// rows lines.
+func (r *Renderer[KID]) writeBuffer(w io.Writer, buf *cellbuf.Buffer, rows int) error {
+	line := &r.line
+	for y := range max(rows, len(buf.Lines)) {
+		line.Reset()
+		if y > 0 {
+			line.WriteByte('\n')
+		}
+		if y < len(buf.Lines) {
+			writeLine(line, buf.Lines[y])
+		}
+		if _, err := w.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package keel
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	gloss "github.com/charmbracelet/lipgloss"
+)
+
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+func TestRenderToMatchesRender(t *testing.T) {
+	border := gloss.NewStyle().Border(gloss.RoundedBorder()).Foreground(gloss.Color("6"))
+	layout := Col(FlexUnit(),
+		Exact(Fixed(3), "header"),
+		Row(FlexUnit(),
+			Wrap(Fixed(8), "nav"),
+			Clip(FlexUnit(), "body"),
+		),
+	)
+	renderer := NewRenderer(layout, func(id string) *gloss.Style {
+		if id == "header" {
+			return &border
+		}
+		return nil
+	}, func(id string, _ FrameInfo) (string, error) {
+		if id == "nav" {
+			return gloss.NewStyle().Bold(true).Render("queues ingest parse"), nil
+		}
+		return id + " 界", nil
+	})
+
+	for _, size := range []Size{{Width: 20, Height: 6}, {Width: 31, Height: 9}, {Width: 20, Height: 6}} {
+		want, err := renderer.Render(size)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got bytes.Buffer
+		if err := renderer.RenderTo(&got, size); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.String() != want {
+			t.Fatalf("expected %q, got %q", want, got.String())
+		}
+	}
+}
+
+func TestRenderToModes(t *testing.T) {
+	renderer, errWidget := tolerantRenderer()
+	size := Size{Width: 16, Height: 3}
+	want, wantErr := renderer.Render(size)
+
+	var got bytes.Buffer
+	err := renderer.RenderTo(&got, size)
+	if !errors.Is(err, errWidget) || err.Error() != wantErr.Error() {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	if got.String() != want {
+		t.Fatalf("expected placeholder output %q, got %q", want, got.String())
+	}
+
+	renderer.Config().SetErrorMode(ErrorModeAbort)
+	got.Reset()
+	if err := renderer.RenderTo(&got, size); !errors.Is(err, errWidget) || got.Len() != 0 {
+		t.Fatalf("expected no output and the frame error, got %q, %v", got.String(), err)
+	}
+
+	renderer = NewRenderer(Overflow(Fixed(3), "a"), nil, func(string, FrameInfo) (string, error) {
+		return "a\nb", nil
+	})
+	renderer.Config().SetDimensionCheck(DimensionCheckRepair)
+	want, _ = renderer.Render(Size{Width: 3, Height: 1})
+	got.Reset()
+	if err := renderer.RenderTo(&got, Size{Width: 3, Height: 1}); err != nil || got.String() != want {
+		t.Fatalf("expected repaired output %q, got %q, %v", want, got.String(), err)
+	}
+
+	errWrite := errors.New("closed")
+	if err := renderer.RenderTo(failingWriter{err: errWrite}, Size{Width: 3, Height: 1}); !errors.Is(err, errWrite) {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
@@ -0,0 +1,11 @@
+// Package tiling edits keel spec trees with tiling window manager
+// operations keyed by frame ID: split a frame, close it and let a sibling
+// absorb its space, swap two frames, rotate the slots of a stack and
+// equalize the whole tree.
+//
+// A [Tree] is immutable; every operation returns a new tree that shares the
+// untouched subtrees of the previous one. Trees are kept normalized: they
+// contain no empty stacks and no single-slot stacks, nested stacks on their
+// parent's axis are flattened where that keeps their proportions, and flex
+// weights are reduced to their smallest whole ratios.
+package tiling
@@ -0,0 +1,122 @@
+package tiling
+
+import (
+	"github.com/trippwill/keel"
+	"github.com/trippwill/keel/core"
+)
+
+// resized is a frame with a different extent. It is focusable exactly when
+// the frame it wraps is.
+type resized[KID keel.KeelID] struct {
+	keel.FrameSpec[KID]
+	extent keel.ExtentConstraint
+}
+
+// Extent implements [core.Spec].
+func (f resized[KID]) Extent() keel.ExtentConstraint { return f.extent }
+
+// Focusable implements [core.Focusable].
+func (f resized[KID]) Focusable() bool {
+	if focusable, ok := f.FrameSpec.(core.Focusable); ok {
+		return focusable.Focusable()
+	}
+	return true
+}
+
+// Unwrap returns the wrapped frame.
+func (f resized[KID]) Unwrap() keel.FrameSpec[KID] { return f.FrameSpec }
+
+// halve splits extent e between a frame and the frame split off it, giving
+// the first half any odd cell. Flex extents must have even units; see
+// [evenUnits]. It reports false when a fixed size or cell cap is below two
+// cells, since the halves could then only be valid by taking more space.
+func halve(e keel.ExtentConstraint) (keel.ExtentConstraint, keel.ExtentConstraint, bool) {
+	if e.Kind == core.ExtentFixed && e.Units < 2 {
+		return e, e, false
+	}
+	if e.Kind == core.ExtentFlex && e.MaxCells == 1 {
+		return e, e, false
+	}
+	a, b := e, e
+	a.Units, b.Units = e.Units-e.Units/2, e.Units/2
+	a.MinCells, b.MinCells = e.MinCells-e.MinCells/2, e.MinCells/2
+	a.MaxCells, b.MaxCells = e.MaxCells-e.MaxCells/2, e.MaxCells/2
+	return a, b, true
+}
+
+// evenUnits doubles every flex weight in extents when the flex extent at
+// index has odd units, so that it can be halved without changing ratios.
+func evenUnits(extents []keel.ExtentConstraint, index int) {
+	e := extents[index]
+	if e.Kind != core.ExtentFlex || e.Units%2 == 0 {
+		return
+	}
+	for i := range extents {
+		if extents[i].Kind == core.ExtentFlex {
+			extents[i].Units *= 2
+		}
+	}
+}
+
+// merge returns the extent of a slot that absorbs the space of a closed
+// sibling. Two fixed extents add up. Otherwise the result is flex with the
+// combined flex weight, at least the cells both reserved, and a maximum only
+// when every flex extent had one.
+func merge(a, b keel.ExtentConstraint) keel.ExtentConstraint {
+	if a.Kind == core.ExtentFixed && b.Kind == core.ExtentFixed {
+		return keel.ExtentConstraint{
+			Kind:     core.ExtentFixed,
+			Units:    a.Units + b.Units,
+			MinCells: a.MinCells + b.MinCells,
+		}
+	}
+	merged := keel.ExtentConstraint{Kind: core.ExtentFlex}
+	capped := true
+	for _, e := range []keel.ExtentConstraint{a, b} {
+		switch e.Kind {
+		case core.ExtentFixed:
+			merged.MinCells += e.Units
+			merged.MaxCells += e.Units
+		case core.ExtentFlex:
+			merged.Units += e.Units
+			merged.MinCells += e.MinCells
+			merged.MaxCells += e.MaxCells
+			capped = capped && e.MaxCells > 0
+		}
+	}
+	if !capped {
+		merged.MaxCells = 0
+	}
+	return merged
+}
+
+// reduceUnits divides every flex weight in extents by their greatest common
+// divisor.
+func reduceUnits(extents []keel.ExtentConstraint) {
+	g := 0
+	for _, e := range extents {
+		if e.Kind == core.ExtentFlex {
+			g = gcd(g, e.Units)
+		}
+	}
+	if g <= 1 {
+		return
+	}
+	for i := range extents {
+		if extents[i].Kind == core.ExtentFlex {
+			extents[i].Units /= g
+		}
+	}
+}
+
+// plainFlex reports whether e is a flex extent without cell bounds.
+func plainFlex(e keel.ExtentConstraint) bool {
+	return e.Kind == core.ExtentFlex && e.MinCells == 0 && e.MaxCells == 0
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,373 @@
+package tiling
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/trippwill/keel"
+	"github.com/trippwill/keel/core"
+	"github.com/trippwill/keel/engine"
+)
+
+var (
+	// ErrLastFrame indicates an attempt to close the only frame of a tree.
+	ErrLastFrame = errors.New("cannot close the last frame")
+	// ErrDuplicateFrameID indicates a new frame whose ID is already in the tree.
+	ErrDuplicateFrameID = errors.New("duplicate frame id")
+	// ErrSplitTooSmall indicates a split along a stack of a frame whose fixed
+	// size or cell cap is below two cells.
+	ErrSplitTooSmall = errors.New("extent too small to split")
+)
+
+// DuplicateFrameIDError indicates a new frame whose ID is already in the tree.
+// It wraps ErrDuplicateFrameID for errors.Is checks.
+type DuplicateFrameIDError struct {
+	ID any
+}
+
+func (e *DuplicateFrameIDError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrDuplicateFrameID, e.ID)
+}
+
+func (e *DuplicateFrameIDError) Unwrap() error {
+	return ErrDuplicateFrameID
+}
+
+// Tree is a normalized spec tree edited by frame ID.
+//
+// Operations rebuild the stacks whose extents they change as splits. Frames
+// whose extents change are wrapped rather than rebuilt: the wrapper reports
+// the new extent, delegates everything else to the frame, including
+// [core.Focusable], and returns the frame from an Unwrap method. Frames are
+// found by [keel.FramePath], so IDs should be unique; [Tree.Split] rejects
+// IDs already in the tree.
+type Tree[KID keel.KeelID] struct {
+	root keel.Spec
+}
+
+// New returns a tree for root, normalized as described in the package
+// documentation.
+func New[KID keel.KeelID](root keel.Spec) Tree[KID] {
+	root, _ = normalize[KID](root)
+	return Tree[KID]{root: root}
+}
+
+// Spec returns the root spec of the tree, for [keel.NewRenderer] or
+// [keel.Renderer.SetSpec].
+func (t Tree[KID]) Spec() keel.Spec {
+	return t.root
+}
+
+// Split splits the frame with the given ID along axis and places frame after
+// it: to its right for [core.AxisHorizontal], below it for
+// [core.AxisVertical]. When the frame's stack already runs along axis, frame
+// joins that stack and the two share the frame's former extent. Otherwise
+// the frame is replaced by a new stack holding both at equal weight. The
+// extent of frame itself is replaced either way. Split fails with a
+// [DuplicateFrameIDError] when the ID of frame is already in the tree, and
+// with [ErrSplitTooSmall] when the frame joins a stack but its fixed size or
+// cell cap cannot be shared.
+func (t Tree[KID]) Split(id KID, axis core.Axis, frame keel.FrameSpec[KID]) (Tree[KID], error) {
+	if frame == nil {
+		return t, keel.ErrSpecMissing
+	}
+	if axis != core.AxisHorizontal && axis != core.AxisVertical {
+		return t, core.ErrInvalidAxis
+	}
+	path, err := t.path(id)
+	if err != nil {
+		return t, err
+	}
+	if _, ok := keel.FramePath(t.root, frame.ID()); ok {
+		return t, &DuplicateFrameIDError{ID: frame.ID()}
+	}
+	target, _ := keel.SlotAt(t.root, path)
+
+	parentPath, index, ok := parentOf(path)
+	if ok {
+		parent, _ := keel.SlotAt(t.root, parentPath)
+		if stack := parent.(keel.StackSpec); stack.Axis() == axis {
+			slots, extents := stackSlots(stack)
+			evenUnits(extents, index)
+			kept, split, ok := halve(extents[index])
+			if !ok {
+				return t, ErrSplitTooSmall
+			}
+			extents[index] = kept
+			slots = slices.Insert(slots, index+1, keel.Spec(frame))
+			extents = slices.Insert(extents, index+1, split)
+			return t.replace(parentPath, rebuild[KID](stack, slots, extents))
+		}
+	}
+
+	pair := engine.NewSplitSpec(axis, target.Extent(),
+		withExtent[KID](target, keel.FlexUnit()),
+		withExtent[KID](frame, keel.FlexUnit()),
+	)
+	return t.replace(path, pair)
+}
+
+// Close removes the frame with the given ID. The previous slot of its stack,
+// or the next one for the first slot, absorbs its extent (see [merge]).
+// Closing the only frame fails with [ErrLastFrame].
+func (t Tree[KID]) Close(id KID) (Tree[KID], error) {
+	path, err := t.path(id)
+	if err != nil {
+		return t, err
+	}
+	parentPath, index, ok := parentOf(path)
+	if !ok {
+		return t, ErrLastFrame
+	}
+	parent, _ := keel.SlotAt(t.root, parentPath)
+	stack := parent.(keel.StackSpec)
+	slots, extents := stackSlots(stack)
+	if len(slots) > 1 {
+		sibling := index - 1
+		if index == 0 {
+			sibling = 1
+		}
+		extents[sibling] = merge(extents[sibling], extents[index])
+	}
+	slots = slices.Delete(slots, index, index+1)
+	extents = slices.Delete(extents, index, index+1)
+	return t.replace(parentPath, rebuild[KID](stack, slots, extents))
+}
+
+// Swap exchanges the frames with IDs a and b. Each takes the other's
+// position and extent, so the arranged geometry is unchanged.
+func (t Tree[KID]) Swap(a, b KID) (Tree[KID], error) {
+	pathA, err := t.path(a)
+	if err != nil {
+		return t, err
+	}
+	pathB, err := t.path(b)
+	if err != nil {
+		return t, err
+	}
+	if pathA == pathB {
+		return t, nil
+	}
+	frameA, _ := keel.SlotAt(t.root, pathA)
+	frameB, _ := keel.SlotAt(t.root, pathB)
+	root, err := keel.ReplaceSlot(t.root, pathA, withExtent[KID](frameB, frameA.Extent()))
+	if err != nil {
+		return t, err
+	}
+	root, err = keel.ReplaceSlot(root, pathB, withExtent[KID](frameA, frameB.Extent()))
+	if err != nil {
+		return t, err
+	}
+	return Tree[KID]{root: root}, nil
+}
+
+// Rotate moves every slot of the stack holding the frame with the given ID
+// n positions forward, wrapping around; negative n rotates backward.
+// Extents stay with their positions. Rotating a lone root frame does
+// nothing.
+func (t Tree[KID]) Rotate(id KID, n int) (Tree[KID], error) {
+	path, err := t.path(id)
+	if err != nil {
+		return t, err
+	}
+	parentPath, _, ok := parentOf(path)
+	if !ok {
+		return t, nil
+	}
+	parent, _ := keel.SlotAt(t.root, parentPath)
+	stack := parent.(keel.StackSpec)
+	slots, extents := stackSlots(stack)
+	rotated := make([]keel.Spec, len(slots))
+	for i, slot := range slots {
+		rotated[((i+n)%len(slots)+len(slots))%len(slots)] = slot
+	}
+	return t.replace(parentPath, rebuild[KID](stack, rotated, extents))
+}
+
+// Equalize gives every slot of every stack the same flex weight, dropping
+// fixed sizes and cell bounds. The root keeps its extent.
+func (t Tree[KID]) Equalize() Tree[KID] {
+	if t.root == nil {
+		return t
+	}
+	return Tree[KID]{root: equalize[KID](t.root, t.root.Extent())}
+}
+
+func equalize[KID keel.KeelID](spec keel.Spec, extent keel.ExtentConstraint) keel.Spec {
+	stack, ok := spec.(keel.StackSpec)
+	if !ok {
+		return withExtent[KID](spec, extent)
+	}
+	slots, _ := stackSlots(stack)
+	for i, slot := range slots {
+		slots[i] = equalize[KID](slot, keel.FlexUnit())
+	}
+	return engine.NewSplitSpec(stack.Axis(), extent, slots...)
+}
+
+func (t Tree[KID]) path(id KID) (string, error) {
+	path, ok := keel.FramePath(t.root, id)
+	if !ok {
+		return "", &keel.UnknownFrameIDError{ID: id}
+	}
+	return path, nil
+}
+
+// replace swaps the spec at path and normalizes the result.
+func (t Tree[KID]) replace(path string, spec keel.Spec) (Tree[KID], error) {
+	root, err := keel.ReplaceSlot(t.root, path, spec)
+	if err != nil {
+		return t, err
+	}
+	return New[KID](root), nil
+}
+
+// parentOf returns the path of the stack holding the slot at path and the
+// slot's index in it.
+func parentOf(path string) (string, int, bool) {
+	cut := strings.LastIndexByte(path, '/')
+	if path == "/" || cut < 0 {
+		return "", 0, false
+	}
+	index, err := strconv.Atoi(path[cut+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	if cut == 0 {
+		return "/", index, true
+	}
+	return path[:cut], index, true
+}
+
+func stackSlots(stack keel.StackSpec) ([]keel.Spec, []keel.ExtentConstraint) {
+	slots := make([]keel.Spec, stack.Len())
+	extents := make([]keel.ExtentConstraint, len(slots))
+	for i := range slots {
+		slots[i], _ = stack.Slot(i)
+		if slots[i] != nil {
+			extents[i] = slots[i].Extent()
+		}
+	}
+	return slots, extents
+}
+
+// rebuild returns stack with the given slots, each set to the extent at the
+// same index.
+func rebuild[KID keel.KeelID](stack keel.StackSpec, slots []keel.Spec, extents []keel.ExtentConstraint) keel.StackSpec {
+	for i, slot := range slots {
+		slots[i] = withExtent[KID](slot, extents[i])
+	}
+	return engine.NewSplitSpec(stack.Axis(), stack.Extent(), slots...)
+}
+
+// withExtent returns spec with the given extent, wrapping frames in a
+// [resized] frame and rebuilding stacks as splits when the extent changes.
+func withExtent[KID keel.KeelID](spec keel.Spec, extent keel.ExtentConstraint) keel.Spec {
+	if spec == nil || spec.Extent() == extent {
+		return spec
+	}
+	switch n := spec.(type) {
+	case keel.StackSpec:
+		slots, _ := stackSlots(n)
+		return engine.NewSplitSpec(n.Axis(), extent, slots...)
+	case resized[KID]:
+		if n.FrameSpec.Extent() == extent {
+			return n.FrameSpec
+		}
+		return resized[KID]{FrameSpec: n.FrameSpec, extent: extent}
+	case keel.FrameSpec[KID]:
+		return resized[KID]{FrameSpec: n, extent: extent}
+	default:
+		return spec
+	}
+}
+
+// normalize removes empty stacks, collapses single-slot stacks into their
+// slot, flattens nested stacks along their parent's axis when all their
+// weights are plain flex, and reduces flex weights. Subtrees that need no
+// change are returned as is and reported unchanged.
+func normalize[KID keel.KeelID](spec keel.Spec) (keel.Spec, bool) {
+	stack, ok := spec.(keel.StackSpec)
+	if !ok {
+		return spec, false
+	}
+	slots, _ := stackSlots(stack)
+	changed := false
+	next := make([]keel.Spec, 0, len(slots))
+	for _, slot := range slots {
+		normalized, slotChanged := normalize[KID](slot)
+		changed = changed || slotChanged
+		if child, ok := normalized.(keel.StackSpec); ok && child.Len() == 0 {
+			changed = true
+			continue
+		}
+		next = append(next, normalized)
+	}
+
+	if len(next) == 1 {
+		return withExtent[KID](next[0], stack.Extent()), true
+	}
+
+	extents := make([]keel.ExtentConstraint, len(next))
+	for i, slot := range next {
+		extents[i] = slot.Extent()
+	}
+	if flattened, flatExtents, ok := flatten(stack.Axis(), next, extents); ok {
+		next, extents = flattened, flatExtents
+		changed = true
+	}
+	reduced := slices.Clone(extents)
+	reduceUnits(reduced)
+	if !slices.Equal(reduced, extents) {
+		changed = true
+	}
+	if !changed {
+		return spec, false
+	}
+	for i, slot := range next {
+		next[i] = withExtent[KID](slot, reduced[i])
+	}
+	return engine.NewSplitSpec(stack.Axis(), stack.Extent(), next...), true
+}
+
+// flatten splices child stacks along axis into their parent when the child
+// and all of its slots have plain flex extents. The child's weight is spread
+// over its slots and the other weights are scaled to keep every ratio.
+func flatten(axis core.Axis, slots []keel.Spec, extents []keel.ExtentConstraint) ([]keel.Spec, []keel.ExtentConstraint, bool) {
+	flattened := false
+	for i := 0; i < len(slots); i++ {
+		child, ok := slots[i].(keel.StackSpec)
+		if !ok || child.Axis() != axis || !plainFlex(extents[i]) {
+			continue
+		}
+		childSlots, childExtents := stackSlots(child)
+		total := 0
+		for _, e := range childExtents {
+			if !plainFlex(e) {
+				total = 0
+				break
+			}
+			total += e.Units
+		}
+		if total == 0 {
+			continue
+		}
+		weight := extents[i].Units
+		for j := range extents {
+			if extents[j].Kind == core.ExtentFlex {
+				extents[j].Units *= total
+			}
+		}
+		for j := range childExtents {
+			childExtents[j].Units *= weight
+		}
+		slots = slices.Replace(slots, i, i+1, childSlots...)
+		extents = slices.Replace(extents, i, i+1, childExtents...)
+		i += len(childSlots) - 1
+		flattened = true
+	}
+	return slots, extents, flattened
+}
@@ -0,0 +1,336 @@
+package tiling
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/trippwill/keel"
+	"github.com/trippwill/keel/core"
+)
+
+// describe renders a tree as nested R(...)/C(...) groups of frame IDs, each
+// slot suffixed with its extent: fN for flex weights and xN for fixed sizes.
+func describe(spec keel.Spec) string {
+	extent := spec.Extent()
+	suffix := fmt.Sprintf("f%d", extent.Units)
+	if extent.Kind == core.ExtentFixed {
+		suffix = fmt.Sprintf("x%d", extent.Units)
+	}
+	switch n := spec.(type) {
+	case keel.StackSpec:
+		parts := make([]string, n.Len())
+		for i := range parts {
+			slot, _ := n.Slot(i)
+			parts[i] = describe(slot)
+		}
+		prefix := "R"
+		if n.Axis() == core.AxisVertical {
+			prefix = "C"
+		}
+		return prefix + "(" + strings.Join(parts, " ") + ")" + suffix
+	case keel.FrameSpec[string]:
+		return n.ID() + suffix
+	default:
+		return "?"
+	}
+}
+
+func frame(id string) keel.FrameSpec[string] {
+	return keel.Exact(keel.FlexUnit(), id)
+}
+
+func TestNewNormalizes(t *testing.T) {
+	tests := []struct {
+		name string
+		root keel.Spec
+		want string
+	}{
+		{
+			name: "single slot stack",
+			root: keel.Row(keel.Flex(2), keel.Col(keel.FlexUnit(), frame("a"))),
+			want: "af2",
+		},
+		{
+			name: "empty stack",
+			root: keel.Row(keel.FlexUnit(), frame("a"), keel.Col(keel.FlexUnit()), frame("b")),
+			want: "R(af1 bf1)f1",
+		},
+		{
+			name: "nested same axis",
+			root: keel.Row(keel.FlexUnit(), frame("a"), keel.Row(keel.FlexUnit(), frame("b"), frame("c"))),
+			want: "R(af2 bf1 cf1)f1",
+		},
+		{
+			name: "nested with fixed slot",
+			root: keel.Row(keel.FlexUnit(), frame("a"), keel.Row(keel.FlexUnit(), keel.Exact(keel.Fixed(3), "b"), frame("c"))),
+			want: "R(af1 R(bx3 cf1)f1)f1",
+		},
+		{
+			name: "reduced weights",
+			root: keel.Col(keel.FlexUnit(), keel.Exact(keel.Flex(4), "a"), keel.Exact(keel.Flex(2), "b")),
+			want: "C(af2 bf1)f1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describe(New[string](tt.root).Spec()); got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTreeOperations(t *testing.T) {
+	base := func() Tree[string] {
+		return New[string](keel.Row(keel.FlexUnit(),
+			frame("a"),
+			keel.Col(keel.FlexUnit(), frame("b"), frame("c")),
+		))
+	}
+
+	tests := []struct {
+		name string
+		edit func(Tree[string]) (Tree[string], error)
+		want string
+	}{
+		{
+			name: "split across",
+			edit: func(tree Tree[string]) (Tree[string], error) {
+				return tree.Split("a", core.AxisVertical, frame("x"))
+			},
+			want: "R(C(af1 xf1)f1 C(bf1 cf1)f1)f1",
+		},
+		{
+			name: "split along",
+			edit: func(tree Tree[string]) (Tree[string], error) {
+				return tree.Split("a", core.AxisHorizontal, frame("x"))
+			},
+			want: "R(af1 xf1 C(bf1 cf1)f2)f1",
+		},
+		{
+			name: "split replaces frame extent",
+			edit: func(tree Tree[string]) (Tree[string], error) {
+				tree, err := tree.Split("b", core.AxisVertical, keel.Exact(keel.Fixed(5), "x"))
+				if err != nil {
+					return tree, err
+				}
+				return tree.Split("x", core.AxisVertical, frame("y"))
+			},
+			want: "R(af1 C(bf2 xf1 yf1 cf4)f1)f1",
+		},
+		{
+			name: "close collapses",
+			edit: func(tree Tree[string]) (Tree[string], error) { return tree.Close("b") },
+			want: "R(af1 cf1)f1",
+		},
+		{
+			name: "close absorbs",
+			edit: func(tree Tree[string]) (Tree[string], error) {
+				tree, err := tree.Split("a", core.AxisHorizontal, frame("x"))
+				if err != nil {
+					return tree, err
+				}
+				return tree.Close("x")
+			},
+			want: "R(af1 C(bf1 cf1)f1)f1",
+		},
+		{
+			name: "close first",
+			edit: func(tree Tree[string]) (Tree[string], error) { return tree.Close("a") },
+			want: "C(bf1 cf1)f1",
+		},
+		{
+			name: "swap",
+			edit: func(tree Tree[string]) (Tree[string], error) { return tree.Swap("a", "c") },
+			want: "R(cf1 C(bf1 af1)f1)f1",
+		},
+		{
+			name: "rotate",
+			edit: func(tree Tree[string]) (Tree[string], error) { return tree.Rotate("b", 1) },
+			want: "R(af1 C(cf1 bf1)f1)f1",
+		},
+		{
+			name: "rotate backward",
+			edit: func(tree Tree[string]) (Tree[string], error) { return tree.Rotate("a", -3) },
+			want: "R(C(bf1 cf1)f1 af1)f1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := base()
+			next, err := tt.edit(tree)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := describe(next.Spec()); got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+			if got := describe(tree.Spec()); got != "R(af1 C(bf1 cf1)f1)f1" {
+				t.Fatalf("expected original tree to be unchanged, got %s", got)
+			}
+		})
+	}
+}
+
+func TestSplitKeepsProportions(t *testing.T) {
+	tree := New[string](keel.Row(keel.FlexUnit(), keel.Exact(keel.Flex(3), "a"), frame("b")))
+	tree, err := tree.Split("a", core.AxisHorizontal, frame("x"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := describe(tree.Spec()); got != "R(af3 xf3 bf2)f1" {
+		t.Fatalf("unexpected tree %s", got)
+	}
+
+	renderer := keel.NewRenderer(tree.Spec(), nil, func(string, keel.FrameInfo) (string, error) { return "", nil })
+	layout, err := renderer.Layout(keel.Size{Width: 8, Height: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, want := range []int{3, 3, 2} {
+		if got := layout.Root.Slots[i].Rect.Width; got != want {
+			t.Fatalf("slot %d: expected width %d, got %d", i, want, got)
+		}
+	}
+
+	tree = New[string](keel.Col(keel.FlexUnit(), keel.Exact(keel.Fixed(5), "a"), frame("b")))
+	tree, err = tree.Split("a", core.AxisVertical, frame("x"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := describe(tree.Spec()); got != "C(ax3 xx2 bf1)f1" {
+		t.Fatalf("unexpected tree %s", got)
+	}
+}
+
+func TestCloseMergesExtents(t *testing.T) {
+	tree := New[string](keel.Row(keel.FlexUnit(), keel.Exact(keel.Fixed(4), "a"), frame("b"), keel.Exact(keel.Fixed(2), "c")))
+	tree, err := tree.Close("a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _ := keel.SlotAt(tree.Spec(), "/0")
+	if got := b.Extent(); got != keel.FlexMin(1, 4) {
+		t.Fatalf("expected b to reserve a's cells, got %+v", got)
+	}
+	tree, err = tree.Close("b")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := describe(tree.Spec()); got != "cf1" {
+		t.Fatalf("unexpected tree %s", got)
+	}
+	if got := tree.Spec().Extent(); got != keel.FlexUnit() {
+		t.Fatalf("expected the root extent, got %+v", got)
+	}
+}
+
+func TestEqualize(t *testing.T) {
+	tree := New[string](keel.Row(keel.Fixed(20),
+		keel.Exact(keel.Fixed(4), "a"),
+		keel.Col(keel.Flex(3), keel.Exact(keel.FlexMin(2, 3), "b"), frame("c")),
+	))
+	if got := describe(tree.Equalize().Spec()); got != "R(af1 C(bf1 cf1)f1)x20" {
+		t.Fatalf("unexpected tree %s", got)
+	}
+}
+
+func TestTreeErrors(t *testing.T) {
+	tree := New[string](frame("a"))
+	if _, err := tree.Close("a"); !errors.Is(err, ErrLastFrame) {
+		t.Fatalf("expected ErrLastFrame, got %v", err)
+	}
+	if _, err := tree.Split("z", core.AxisHorizontal, frame("x")); !errors.Is(err, keel.ErrUnknownFrameID) {
+		t.Fatalf("expected ErrUnknownFrameID, got %v", err)
+	}
+	if _, err := tree.Split("a", core.Axis(9), frame("x")); !errors.Is(err, core.ErrInvalidAxis) {
+		t.Fatalf("expected ErrInvalidAxis, got %v", err)
+	}
+	if _, err := tree.Split("a", core.AxisHorizontal, nil); !errors.Is(err, keel.ErrSpecMissing) {
+		t.Fatalf("expected ErrSpecMissing, got %v", err)
+	}
+	var dup *DuplicateFrameIDError
+	if _, err := tree.Split("a", core.AxisHorizontal, keel.Clip(keel.FlexUnit(), "a")); !errors.As(err, &dup) || dup.ID != "a" {
+		t.Fatalf("expected DuplicateFrameIDError for a, got %v", err)
+	}
+	if !errors.Is(dup, ErrDuplicateFrameID) || dup.Error() != "duplicate frame id: a" {
+		t.Fatalf("unexpected duplicate error %q", dup.Error())
+	}
+	if _, err := tree.Swap("a", "z"); !errors.Is(err, keel.ErrUnknownFrameID) {
+		t.Fatalf("expected ErrUnknownFrameID, got %v", err)
+	}
+	if rotated, err := tree.Rotate("a", 1); err != nil || describe(rotated.Spec()) != "af1" {
+		t.Fatalf("expected lone frame rotate to do nothing, got %v", err)
+	}
+}
+
+func TestOperationsKeepUnfocusable(t *testing.T) {
+	base := func() Tree[string] {
+		return New[string](keel.Row(keel.FlexUnit(),
+			keel.Unfocusable(keel.Exact(keel.Flex(3), "status")),
+			frame("a"),
+			keel.Col(keel.FlexUnit(), frame("b"), frame("c")),
+		))
+	}
+
+	tests := []struct {
+		name string
+		edit func(Tree[string]) (Tree[string], error)
+	}{
+		{"split", func(tree Tree[string]) (Tree[string], error) {
+			return tree.Split("status", core.AxisHorizontal, frame("x"))
+		}},
+		{"split across", func(tree Tree[string]) (Tree[string], error) {
+			return tree.Split("status", core.AxisVertical, frame("x"))
+		}},
+		{"close", func(tree Tree[string]) (Tree[string], error) { return tree.Close("a") }},
+		{"swap", func(tree Tree[string]) (Tree[string], error) { return tree.Swap("status", "b") }},
+		{"rotate", func(tree Tree[string]) (Tree[string], error) { return tree.Rotate("status", 1) }},
+		{"equalize", func(tree Tree[string]) (Tree[string], error) { return tree.Equalize(), nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := tt.edit(base())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, ok := keel.FramePath(next.Spec(), "status"); !ok {
+				t.Fatalf("expected status frame in %s", describe(next.Spec()))
+			}
+			order := keel.NewRenderer[string](next.Spec(), nil, nil).Focus().Order()
+			for _, id := range order {
+				if id == "status" {
+					t.Fatalf("expected status to stay unfocusable, got order %v", order)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitKeepsCellCaps(t *testing.T) {
+	tree := New[string](keel.Row(keel.FlexUnit(), keel.Exact(keel.FlexMax(2, 3), "a"), frame("b")))
+	tree, err := tree.Split("a", core.AxisHorizontal, frame("x"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a, _ := keel.SlotAt(tree.Spec(), "/0")
+	x, _ := keel.SlotAt(tree.Spec(), "/1")
+	if got := a.Extent().MaxCells + x.Extent().MaxCells; got != 3 {
+		t.Fatalf("expected halves to share a cap of 3, got %+v and %+v", a.Extent(), x.Extent())
+	}
+
+	for _, extent := range []keel.ExtentConstraint{keel.FlexMax(1, 1), keel.Fixed(1)} {
+		tree := New[string](keel.Row(keel.FlexUnit(), keel.Exact(extent, "a"), frame("b")))
+		if _, err := tree.Split("a", core.AxisHorizontal, frame("x")); !errors.Is(err, ErrSplitTooSmall) {
+			t.Fatalf("%+v: expected ErrSplitTooSmall, got %v", extent, err)
+		}
+		if _, err := tree.Split("a", core.AxisVertical, frame("x")); err != nil {
+			t.Fatalf("%+v: expected split across to succeed, got %v", extent, err)
+		}
+	}
+}
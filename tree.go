@@ -0,0 +1,326 @@
+package keel
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/trippwill/keel/core"
+	"github.com/trippwill/keel/engine"
+)
+
+// Tree operations edit a spec tree without mutating it. Each returns a new
+// root that shares every subtree off the edited path with the original;
+// stacks on the path are rebuilt as splits with the same axis and extent.
+//
+// Slots are addressed by the slash-delimited paths used by layouts, such as
+// "/0/1" for the second slot of the first slot of the root. The root is at
+// "/" and is not a slot of any stack. Malformed paths, indexes out of range
+// and paths through frames fail with [UnknownPathError].
+
+// SlotAt returns the spec at path.
+func SlotAt(root Spec, path string) (Spec, bool) {
+	indexes, ok := parsePath(path)
+	if !ok {
+		return nil, false
+	}
+	return specAt(root, indexes)
+}
+
+// InsertSlot inserts slot before the slot at path. The last index of path
+// may equal the length of its stack to append.
+func InsertSlot(root Spec, path string, slot Spec) (Spec, error) {
+	parent, index, err := splitSlotPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return editStack(root, parent, func(slots []Spec) ([]Spec, error) {
+		if index > len(slots) {
+			return nil, &UnknownPathError{Path: path}
+		}
+		if slot == nil {
+			return nil, nilSlotError(parent, index)
+		}
+		return slices.Insert(slots, index, slot), nil
+	})
+}
+
+// RemoveSlot removes the slot at path and returns the new root with the
+// removed spec. The stack that held the slot is kept even when it becomes
+// empty.
+func RemoveSlot(root Spec, path string) (Spec, Spec, error) {
+	parent, index, err := splitSlotPath(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var removed Spec
+	next, err := editStack(root, parent, func(slots []Spec) ([]Spec, error) {
+		if index >= len(slots) {
+			return nil, &UnknownPathError{Path: path}
+		}
+		removed = slots[index]
+		return slices.Delete(slots, index, index+1), nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return next, removed, nil
+}
+
+// ReplaceSlot replaces the spec at path. Replacing "/" returns slot.
+func ReplaceSlot(root Spec, path string, slot Spec) (Spec, error) {
+	if path == "/" {
+		if slot == nil {
+			return nil, nilSlotError(nil, -1)
+		}
+		return slot, nil
+	}
+	parent, index, err := splitSlotPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return editStack(root, parent, func(slots []Spec) ([]Spec, error) {
+		if index >= len(slots) {
+			return nil, &UnknownPathError{Path: path}
+		}
+		if slot == nil {
+			return nil, nilSlotError(parent, index)
+		}
+		slots[index] = slot
+		return slots, nil
+	})
+}
+
+// MoveSlot moves the slot at from so that it is inserted before the slot
+// at to. Both paths refer to the tree before the move, so moving "/0" to
+// "/2" places it between the original second and third slots. Moving a
+// slot into its own subtree fails with [ErrMoveIntoSelf].
+func MoveSlot(root Spec, from, to string) (Spec, error) {
+	fromParent, fromIndex, err := splitSlotPath(from)
+	if err != nil {
+		return nil, err
+	}
+	toParent, toIndex, err := splitSlotPath(to)
+	if err != nil {
+		return nil, err
+	}
+	fromPath := append(slices.Clip(fromParent), fromIndex)
+	toPath := append(slices.Clip(toParent), toIndex)
+	if len(toPath) > len(fromPath) && slices.Equal(toPath[:len(fromPath)], fromPath) {
+		return nil, ErrMoveIntoSelf
+	}
+	target, _ := specAt(root, toParent)
+	if stack, ok := target.(core.StackSpec); !ok || toIndex > stack.Len() {
+		return nil, &UnknownPathError{Path: to}
+	}
+
+	next, moved, err := RemoveSlot(root, from)
+	if err != nil {
+		return nil, err
+	}
+	// Removing the slot shifts later siblings of from, and everything below
+	// them, up by one.
+	depth := len(fromPath) - 1
+	if len(toPath) > depth && slices.Equal(toPath[:depth], fromPath[:depth]) && toPath[depth] > fromIndex {
+		toPath[depth]--
+	}
+	return InsertSlot(next, formatPath(toPath), moved)
+}
+
+// FramePath returns the path of the first frame with the given ID in
+// document order.
+func FramePath[KID KeelID](root Spec, id KID) (string, bool) {
+	indexes, ok := framePath(root, id, nil)
+	if !ok {
+		return "", false
+	}
+	return formatPath(indexes), true
+}
+
+// InsertBeforeFrame inserts slot into the stack holding the frame with the
+// given ID, just before the frame.
+func InsertBeforeFrame[KID KeelID](root Spec, id KID, slot Spec) (Spec, error) {
+	return insertBesideFrame(root, id, slot, 0)
+}
+
+// InsertAfterFrame inserts slot into the stack holding the frame with the
+// given ID, just after the frame.
+func InsertAfterFrame[KID KeelID](root Spec, id KID, slot Spec) (Spec, error) {
+	return insertBesideFrame(root, id, slot, 1)
+}
+
+// RemoveFrame removes the frame with the given ID.
+func RemoveFrame[KID KeelID](root Spec, id KID) (Spec, error) {
+	path, err := requireFramePath(root, id)
+	if err != nil {
+		return nil, err
+	}
+	next, _, err := RemoveSlot(root, path)
+	return next, err
+}
+
+// ReplaceFrame replaces the frame with the given ID.
+func ReplaceFrame[KID KeelID](root Spec, id KID, slot Spec) (Spec, error) {
+	path, err := requireFramePath(root, id)
+	if err != nil {
+		return nil, err
+	}
+	return ReplaceSlot(root, path, slot)
+}
+
+// MoveFrame moves the frame with the given ID before the slot at to, with
+// the path semantics of [MoveSlot].
+func MoveFrame[KID KeelID](root Spec, id KID, to string) (Spec, error) {
+	path, err := requireFramePath(root, id)
+	if err != nil {
+		return nil, err
+	}
+	return MoveSlot(root, path, to)
+}
+
+func insertBesideFrame[KID KeelID](root Spec, id KID, slot Spec, offset int) (Spec, error) {
+	indexes, ok := framePath(root, id, nil)
+	if !ok {
+		return nil, &UnknownFrameIDError{ID: id}
+	}
+	if len(indexes) == 0 {
+		return nil, &UnknownPathError{Path: "/"}
+	}
+	indexes[len(indexes)-1] += offset
+	return InsertSlot(root, formatPath(indexes), slot)
+}
+
+func requireFramePath[KID KeelID](root Spec, id KID) (string, error) {
+	path, ok := FramePath(root, id)
+	if !ok {
+		return "", &UnknownFrameIDError{ID: id}
+	}
+	return path, nil
+}
+
+func framePath[KID KeelID](spec Spec, id KID, indexes []int) ([]int, bool) {
+	switch n := spec.(type) {
+	case core.StackSpec:
+		for i := range n.Len() {
+			slot, ok := n.Slot(i)
+			if !ok || slot == nil {
+				continue
+			}
+			if found, ok := framePath(slot, id, append(indexes, i)); ok {
+				return found, true
+			}
+		}
+	case core.FrameSpec[KID]:
+		if n.ID() == id {
+			return slices.Clone(indexes), true
+		}
+	}
+	return nil, false
+}
+
+// editStack returns a copy of root with the stack at parent rebuilt from the
+// slots returned by edit. edit receives a copy of the stack's slots.
+func editStack(root Spec, parent []int, edit func([]Spec) ([]Spec, error)) (Spec, error) {
+	return editStackAt(root, parent, parent, edit)
+}
+
+func editStackAt(spec Spec, parent, rest []int, edit func([]Spec) ([]Spec, error)) (Spec, error) {
+	stack, ok := spec.(core.StackSpec)
+	if !ok {
+		return nil, &UnknownPathError{Path: formatPath(parent[:len(parent)-len(rest)])}
+	}
+	slots := stackSlots(stack)
+	if len(rest) == 0 {
+		next, err := edit(slots)
+		if err != nil {
+			return nil, err
+		}
+		return rebuildStack(stack, next), nil
+	}
+	index := rest[0]
+	if index >= len(slots) {
+		return nil, &UnknownPathError{Path: formatPath(parent[:len(parent)-len(rest)+1])}
+	}
+	child, err := editStackAt(slots[index], parent, rest[1:], edit)
+	if err != nil {
+		return nil, err
+	}
+	slots[index] = child
+	return rebuildStack(stack, slots), nil
+}
+
+func stackSlots(stack core.StackSpec) []Spec {
+	slots := make([]Spec, stack.Len())
+	for i := range slots {
+		slots[i], _ = stack.Slot(i)
+	}
+	return slots
+}
+
+func rebuildStack(stack core.StackSpec, slots []Spec) StackSpec {
+	return engine.NewSplitSpec(stack.Axis(), stack.Extent(), slots...)
+}
+
+func specAt(spec Spec, indexes []int) (Spec, bool) {
+	for _, index := range indexes {
+		stack, ok := spec.(core.StackSpec)
+		if !ok {
+			return nil, false
+		}
+		slot, ok := stack.Slot(index)
+		if !ok || slot == nil {
+			return nil, false
+		}
+		spec = slot
+	}
+	return spec, true
+}
+
+// splitSlotPath parses the path of a slot into its stack path and index.
+func splitSlotPath(path string) ([]int, int, error) {
+	indexes, ok := parsePath(path)
+	if !ok || len(indexes) == 0 {
+		return nil, 0, &UnknownPathError{Path: path}
+	}
+	return indexes[:len(indexes)-1], indexes[len(indexes)-1], nil
+}
+
+// parsePath returns the slot indexes of path. Only the root "/" may end in a
+// slash, and no segment may be empty.
+func parsePath(path string) ([]int, bool) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, false
+	}
+	if path == "/" {
+		return nil, true
+	}
+	var indexes []int
+	for _, part := range strings.Split(path[1:], "/") {
+		index, err := strconv.Atoi(part)
+		if err != nil || index < 0 {
+			return nil, false
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, true
+}
+
+func formatPath(indexes []int) string {
+	if len(indexes) == 0 {
+		return "/"
+	}
+	var b strings.Builder
+	for _, index := range indexes {
+		b.WriteByte('/')
+		b.WriteString(strconv.Itoa(index))
+	}
+	return b.String()
+}
+
+func nilSlotError(parent []int, index int) error {
+	path := ""
+	if parent != nil {
+		path = formatPath(parent)
+	}
+	return convertError(&core.SlotError{Index: index, Reason: core.ErrNilSlot, Path: path})
+}
@@ -0,0 +1,223 @@
+package keel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/trippwill/keel/core"
+)
+
+// treeString renders a spec tree as nested R(...)/C(...) groups of frame IDs.
+func treeString(spec Spec) string {
+	switch n := spec.(type) {
+	case core.StackSpec:
+		parts := make([]string, n.Len())
+		for i := range parts {
+			slot, _ := n.Slot(i)
+			parts[i] = treeString(slot)
+		}
+		prefix := "R"
+		if n.Axis() == core.AxisVertical {
+			prefix = "C"
+		}
+		return prefix + "(" + strings.Join(parts, " ") + ")"
+	case FrameSpec[string]:
+		return n.ID()
+	default:
+		return "?"
+	}
+}
+
+func testTree() Spec {
+	return Row(FlexUnit(),
+		Exact(FlexUnit(), "a"),
+		Col(FlexUnit(),
+			Exact(FlexUnit(), "b"),
+			Exact(FlexUnit(), "c"),
+		),
+		Exact(FlexUnit(), "d"),
+	)
+}
+
+func TestTreeOperations(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(Spec) (Spec, error)
+		want string
+	}{
+		{
+			name: "insert nested",
+			edit: func(root Spec) (Spec, error) { return InsertSlot(root, "/1/1", Exact(FlexUnit(), "x")) },
+			want: "R(a C(b x c) d)",
+		},
+		{
+			name: "insert append",
+			edit: func(root Spec) (Spec, error) { return InsertSlot(root, "/3", Exact(FlexUnit(), "x")) },
+			want: "R(a C(b c) d x)",
+		},
+		{
+			name: "remove",
+			edit: func(root Spec) (Spec, error) {
+				next, _, err := RemoveSlot(root, "/1/0")
+				return next, err
+			},
+			want: "R(a C(c) d)",
+		},
+		{
+			name: "replace root",
+			edit: func(root Spec) (Spec, error) { return ReplaceSlot(root, "/", Exact(FlexUnit(), "x")) },
+			want: "x",
+		},
+		{
+			name: "move forward in stack",
+			edit: func(root Spec) (Spec, error) { return MoveSlot(root, "/0", "/2") },
+			want: "R(C(b c) a d)",
+		},
+		{
+			name: "move into later sibling",
+			edit: func(root Spec) (Spec, error) { return MoveSlot(root, "/0", "/1/2") },
+			want: "R(C(b c a) d)",
+		},
+		{
+			name: "move out of stack",
+			edit: func(root Spec) (Spec, error) { return MoveSlot(root, "/1/1", "/0") },
+			want: "R(c a C(b) d)",
+		},
+		{
+			name: "move in place",
+			edit: func(root Spec) (Spec, error) { return MoveSlot(root, "/2", "/3") },
+			want: "R(a C(b c) d)",
+		},
+		{
+			name: "insert before frame",
+			edit: func(root Spec) (Spec, error) { return InsertBeforeFrame(root, "c", Exact(FlexUnit(), "x")) },
+			want: "R(a C(b x c) d)",
+		},
+		{
+			name: "insert after frame",
+			edit: func(root Spec) (Spec, error) { return InsertAfterFrame(root, "c", Exact(FlexUnit(), "x")) },
+			want: "R(a C(b c x) d)",
+		},
+		{
+			name: "remove frame",
+			edit: func(root Spec) (Spec, error) { return RemoveFrame(root, "b") },
+			want: "R(a C(c) d)",
+		},
+		{
+			name: "replace frame",
+			edit: func(root Spec) (Spec, error) { return ReplaceFrame(root, "d", Exact(FlexUnit(), "x")) },
+			want: "R(a C(b c) x)",
+		},
+		{
+			name: "move frame",
+			edit: func(root Spec) (Spec, error) { return MoveFrame(root, "d", "/1/0") },
+			want: "R(a C(d b c))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := testTree()
+			next, err := tt.edit(root)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := treeString(next); got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+			if got := treeString(root); got != "R(a C(b c) d)" {
+				t.Fatalf("expected original tree to be unchanged, got %s", got)
+			}
+		})
+	}
+}
+
+func TestTreeOperationsShareSubtrees(t *testing.T) {
+	root := testTree()
+	next, err := InsertSlot(root, "/3", Exact(FlexUnit(), "x"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	before, _ := SlotAt(root, "/1")
+	after, _ := SlotAt(next, "/1")
+	if !sameSpec(before, after) {
+		t.Fatalf("expected untouched subtree to be shared")
+	}
+}
+
+// sameSpec compares stacks by identity of their slots.
+func sameSpec(a, b Spec) bool {
+	sa, ok := a.(StackSpec)
+	sb, okB := b.(StackSpec)
+	if !ok || !okB || sa.Len() != sb.Len() {
+		return false
+	}
+	for i := range sa.Len() {
+		x, _ := sa.Slot(i)
+		y, _ := sb.Slot(i)
+		if x != y {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTreeOperationErrors(t *testing.T) {
+	root := testTree()
+	var pathErr *UnknownPathError
+
+	for _, path := range []string{"", "/", "0", "/x", "/4", "/0/0", "/1/3", "//", "/1/", "/1//0", "//1"} {
+		if _, err := InsertSlot(root, path, Exact(FlexUnit(), "x")); !errors.As(err, &pathErr) {
+			t.Fatalf("insert %q: expected UnknownPathError, got %v", path, err)
+		}
+	}
+	if _, _, err := RemoveSlot(root, "/3"); !errors.Is(err, ErrUnknownPath) {
+		t.Fatalf("expected ErrUnknownPath, got %v", err)
+	}
+	if _, err := ReplaceSlot(root, "/1/2", Exact(FlexUnit(), "x")); !errors.Is(err, ErrUnknownPath) {
+		t.Fatalf("expected ErrUnknownPath, got %v", err)
+	}
+	var specErr *SpecError
+	if _, err := InsertSlot(root, "/0", nil); !errors.As(err, &specErr) || specErr.Kind != SpecKindSlot {
+		t.Fatalf("expected slot SpecError, got %v", err)
+	}
+	if _, err := MoveSlot(root, "/1", "/1/0"); !errors.Is(err, ErrMoveIntoSelf) {
+		t.Fatalf("expected ErrMoveIntoSelf, got %v", err)
+	}
+	if _, err := MoveSlot(root, "/0", "/5"); !errors.Is(err, ErrUnknownPath) {
+		t.Fatalf("expected ErrUnknownPath, got %v", err)
+	}
+	if _, err := RemoveFrame(root, "z"); !errors.Is(err, ErrUnknownFrameID) {
+		t.Fatalf("expected ErrUnknownFrameID, got %v", err)
+	}
+	if _, err := InsertAfterFrame(Spec(Exact(FlexUnit(), "a")), "a", Exact(FlexUnit(), "x")); !errors.Is(err, ErrUnknownPath) {
+		t.Fatalf("expected ErrUnknownPath for a root frame, got %v", err)
+	}
+	if path, ok := FramePath(root, "c"); !ok || path != "/1/1" {
+		t.Fatalf("expected /1/1, got %q, %v", path, ok)
+	}
+	for _, path := range []string{"/1/", "/1//1", "//1"} {
+		if _, ok := SlotAt(root, path); ok {
+			t.Fatalf("expected no slot at %q", path)
+		}
+	}
+}
+
+func TestRendererSetSpec(t *testing.T) {
+	root := Row(FlexUnit(), Exact(FlexUnit(), "a"))
+	renderer := NewRenderer(root, nil, makeContentProvider("x"))
+	size := Size{Width: 4, Height: 1}
+	if out, err := renderer.Render(size); err != nil || out != "x   " {
+		t.Fatalf("unexpected render %q, %v", out, err)
+	}
+
+	next, err := InsertAfterFrame(root, "a", Exact(FlexUnit(), "b"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	renderer.SetSpec(next)
+	if out, err := renderer.Render(size); err != nil || out != "x x " {
+		t.Fatalf("unexpected render %q, %v", out, err)
+	}
+}